@@ -1,13 +1,19 @@
 package main
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/beacon-blockchain/sdk-go/shim"
+	"github.com/beacon-blockchain/sdk-go/shim/policy"
 )
 
 // GatewayManagementChaincode implements the Chaincode interface for gateway management
@@ -17,11 +23,13 @@ type GatewayManagementChaincode struct{}
 type Gateway struct {
 	ID              string            `json:"id"`
 	PublicKey       string            `json:"publicKey"`
+	Algorithm       string            `json:"algorithm"`
 	OrganizationID  string            `json:"organizationID"`
 	Status          string            `json:"status"`
 	RegistrationTime int64            `json:"registrationTime"`
 	LastHeartbeat   int64            `json:"lastHeartbeat"`
 	Metadata        map[string]string `json:"metadata"`
+	Collection      string            `json:"collection,omitempty"`
 }
 
 // AccessPolicy represents an access control policy
@@ -35,15 +43,23 @@ type AccessPolicy struct {
 	Version     int      `json:"version"`
 }
 
-// Rule represents a single access control rule
+// Rule represents a single access control rule. Conditions are evaluated
+// by the shim/policy engine's built-in DSL (attribute==value, time-of-day
+// windows, IP CIDR match). Effect defaults to "allow" when empty; Priority
+// is only consulted under the "priority" conflict strategy.
 type Rule struct {
 	Resource   string   `json:"resource"`
 	Action     string   `json:"action"`
 	Principals []string `json:"principals"`
 	Conditions []string `json:"conditions"`
+	Effect     string   `json:"effect,omitempty"`
+	Priority   int      `json:"priority,omitempty"`
 }
 
-// AuditLog represents an audit log entry
+// AuditLog represents an audit log entry. PrevHash/EntryHash form a hash
+// chain across entries (see appendAuditEntry); batches of entries are
+// periodically sealed under a Merkle root by sealAuditBatch so external
+// auditors can verify integrity without trusting the peer.
 type AuditLog struct {
 	ID            string `json:"id"`
 	Timestamp     int64  `json:"timestamp"`
@@ -53,6 +69,46 @@ type AuditLog struct {
 	Success       bool   `json:"success"`
 	ErrorMessage  string `json:"errorMessage,omitempty"`
 	TransactionID string `json:"transactionID"`
+	PrevHash      string `json:"prevHash"`
+	EntryHash     string `json:"entryHash"`
+	Collection    string `json:"collection,omitempty"`
+}
+
+// AuditBatch is a Merkle-sealed batch of audit entries, persisted under
+// "audit_root:<batchID>" so external auditors can verify inclusion without
+// replaying the whole chain
+type AuditBatch struct {
+	BatchID  string   `json:"batchID"`
+	FromID   string   `json:"fromID"`
+	ToID     string   `json:"toID"`
+	Leaves   []string `json:"leaves"`
+	Root     string   `json:"root"`
+	SealedAt int64    `json:"sealedAt"`
+}
+
+// auditBatchPointer records which sealed batch (and leaf index within it)
+// an audit entry belongs to, under "audit_batch_of:<auditID>"
+type auditBatchPointer struct {
+	BatchID string `json:"batchID"`
+	Index   int    `json:"index"`
+}
+
+// AuditProof is a Merkle inclusion proof for a single audit entry
+type AuditProof struct {
+	AuditID string   `json:"auditID"`
+	BatchID string   `json:"batchID"`
+	Root    string   `json:"root"`
+	Leaf    string   `json:"leaf"`
+	Path    []string `json:"path"`
+}
+
+// AuditChainVerification is the result of walking an audit chain's
+// PrevHash links looking for gaps or tampering
+type AuditChainVerification struct {
+	Valid       bool   `json:"valid"`
+	EntriesSeen int    `json:"entriesSeen"`
+	BrokenAtID  string `json:"brokenAtID,omitempty"`
+	Reason      string `json:"reason,omitempty"`
 }
 
 // Init initializes the chaincode
@@ -116,6 +172,8 @@ func (cc *GatewayManagementChaincode) Invoke(stub shim.ChaincodeStubInterface) s
 		return cc.deactivateGateway(stub, args)
 	case "heartbeat":
 		return cc.heartbeat(stub, args)
+	case "requestHeartbeatChallenge":
+		return cc.requestHeartbeatChallenge(stub, args)
 	case "createPolicy":
 		return cc.createPolicy(stub, args)
 	case "updatePolicy":
@@ -128,21 +186,56 @@ func (cc *GatewayManagementChaincode) Invoke(stub shim.ChaincodeStubInterface) s
 		return cc.auditLog(stub, args)
 	case "queryAuditLogs":
 		return cc.queryAuditLogs(stub, args)
+	case "queryGatewaysRich":
+		return cc.queryGatewaysRich(stub, args)
+	case "queryPoliciesRich":
+		return cc.queryPoliciesRich(stub, args)
+	case "queryAuditLogsRich":
+		return cc.queryAuditLogsRich(stub, args)
+	case "checkAccess":
+		return cc.checkAccess(stub, args)
+	case "sealAuditBatch":
+		return cc.sealAuditBatch(stub, args)
+	case "getAuditProof":
+		return cc.getAuditProof(stub, args)
+	case "verifyAuditChain":
+		return cc.verifyAuditChain(stub, args)
+	case "sweepLiveness":
+		return cc.sweepLiveness(stub, args)
+	case "reactivateGateway":
+		return cc.reactivateGateway(stub, args)
+	case "getGatewayHealth":
+		return cc.getGatewayHealth(stub, args)
+	case "attachSecretRef":
+		return cc.attachSecretRef(stub, args)
+	case "verifySecretRef":
+		return cc.verifySecretRef(stub, args)
 	default:
 		return shim.Error(fmt.Sprintf("Unknown function: %s", function))
 	}
 }
 
-// registerGateway registers a new gateway in the network
+// registerGateway registers a new gateway in the network. To prevent a
+// client from impersonating a gateway by simply supplying its public key,
+// the caller must prove possession of the matching private key: signature
+// must verify, under algorithm, against the canonical payload
+// "gatewayID|organizationID|nonce|txID".
 func (cc *GatewayManagementChaincode) registerGateway(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 3); err != nil {
+	if err := shim.ValidateArgsRange(args, 6, 7); err != nil {
 		return shim.Error(err.Error())
 	}
-	
+
 	gatewayID := args[0]
 	publicKey := args[1]
 	organizationID := args[2]
-	
+	algorithm := args[3]
+	nonce := args[4]
+	signatureEncoded := args[5]
+	collection := ""
+	if len(args) > 6 {
+		collection = args[6]
+	}
+
 	// Check if gateway already exists
 	existing, err := stub.GetState("gateway:" + gatewayID)
 	if err != nil {
@@ -151,26 +244,33 @@ func (cc *GatewayManagementChaincode) registerGateway(stub shim.ChaincodeStubInt
 	if existing != nil {
 		return shim.Error(fmt.Sprintf("Gateway already exists: %s", gatewayID))
 	}
-	
+
+	if err := verifyAttestation(stub, publicKey, algorithm, signatureEncoded, gatewayID, organizationID, nonce); err != nil {
+		cc.logAudit(stub, gatewayID, "REGISTER_GATEWAY", "gateway:"+gatewayID, false, err.Error())
+		return shim.Error(fmt.Sprintf("Signature verification failed: %s", err.Error()))
+	}
+
 	// Create new gateway
 	gateway := Gateway{
 		ID:              gatewayID,
 		PublicKey:       publicKey,
+		Algorithm:       algorithm,
 		OrganizationID:  organizationID,
 		Status:          "active",
 		RegistrationTime: time.Now().Unix(),
 		LastHeartbeat:   time.Now().Unix(),
 		Metadata:        make(map[string]string),
+		Collection:      collection,
 	}
-	
+
 	// Store gateway
 	err = shim.PutStateAsJSON(stub, "gateway:"+gatewayID, gateway)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to store gateway: %s", err.Error()))
 	}
-	
+
 	// Log audit event
-	cc.logAudit(stub, gatewayID, "REGISTER_GATEWAY", "gateway:"+gatewayID, true, "")
+	cc.logAuditCollection(stub, gatewayID, "REGISTER_GATEWAY", "gateway:"+gatewayID, collection, true, "")
 	
 	// Emit event
 	eventPayload, _ := json.Marshal(map[string]interface{}{
@@ -198,23 +298,34 @@ func (cc *GatewayManagementChaincode) updateGateway(stub shim.ChaincodeStubInter
 		return shim.Error(fmt.Sprintf("Gateway not found: %s", gatewayID))
 	}
 	
-	// Update metadata from key-value pairs in args
+	// Update metadata from key-value pairs in args. When the gateway was
+	// registered against a private collection, the value is routed there
+	// and only its hash is kept on the main ledger.
 	for i := 1; i < len(args); i += 2 {
 		if i+1 < len(args) {
 			key := args[i]
 			value := args[i+1]
-			gateway.Metadata[key] = value
+
+			if gateway.Collection != "" {
+				if err := stub.PutPrivateData(gateway.Collection, gatewayID+":"+key, []byte(value)); err != nil {
+					return shim.Error(fmt.Sprintf("Failed to store private metadata %s: %s", key, err.Error()))
+				}
+				hash := sha256.Sum256([]byte(value))
+				gateway.Metadata[key] = hex.EncodeToString(hash[:])
+			} else {
+				gateway.Metadata[key] = value
+			}
 		}
 	}
-	
+
 	// Update gateway
 	err = shim.PutStateAsJSON(stub, "gateway:"+gatewayID, gateway)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to update gateway: %s", err.Error()))
 	}
-	
+
 	// Log audit event
-	cc.logAudit(stub, gatewayID, "UPDATE_GATEWAY", "gateway:"+gatewayID, true, "")
+	cc.logAuditCollection(stub, gatewayID, "UPDATE_GATEWAY", "gateway:"+gatewayID, gateway.Collection, true, "")
 	
 	return shim.Success([]byte(fmt.Sprintf("Gateway %s updated successfully", gatewayID)))
 }
@@ -306,31 +417,397 @@ func (cc *GatewayManagementChaincode) deactivateGateway(stub shim.ChaincodeStubI
 
 // heartbeat updates the last heartbeat timestamp for a gateway
 func (cc *GatewayManagementChaincode) heartbeat(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 1); err != nil {
+	if err := shim.ValidateArgs(args, 2); err != nil {
 		return shim.Error(err.Error())
 	}
-	
+
 	gatewayID := args[0]
-	
+	signatureEncoded := args[1]
+
 	// Get existing gateway
 	var gateway Gateway
 	err := shim.GetStateAsJSON(stub, "gateway:"+gatewayID, &gateway)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Gateway not found: %s", gatewayID))
 	}
-	
+
+	nonceBytes, err := stub.GetState("challenge:" + gatewayID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to load heartbeat challenge: %s", err.Error()))
+	}
+	if nonceBytes == nil {
+		return shim.Error(fmt.Sprintf("No outstanding heartbeat challenge for gateway %s; call requestHeartbeatChallenge first", gatewayID))
+	}
+	nonce := string(nonceBytes)
+
+	if err := verifyAttestation(stub, gateway.PublicKey, gateway.Algorithm, signatureEncoded, gatewayID, nonce); err != nil {
+		cc.logAudit(stub, gatewayID, "HEARTBEAT", "gateway:"+gatewayID, false, err.Error())
+		return shim.Error(fmt.Sprintf("Signature verification failed: %s", err.Error()))
+	}
+
+	// Consume the nonce so a captured signature cannot be replayed; the
+	// gateway must request a fresh challenge before its next heartbeat
+	if err := stub.DelState("challenge:" + gatewayID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to rotate heartbeat challenge: %s", err.Error()))
+	}
+
 	// Update heartbeat
 	gateway.LastHeartbeat = time.Now().Unix()
-	
+
 	// Store updated gateway
 	err = shim.PutStateAsJSON(stub, "gateway:"+gatewayID, gateway)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to update heartbeat: %s", err.Error()))
 	}
-	
+
+	cc.logAudit(stub, gatewayID, "HEARTBEAT", "gateway:"+gatewayID, true, "")
+
 	return shim.Success([]byte("Heartbeat updated"))
 }
 
+// requestHeartbeatChallenge issues a short-lived, single-use nonce that the
+// gateway must sign (together with its ID) to prove liveness and key
+// possession in its next heartbeat call
+func (cc *GatewayManagementChaincode) requestHeartbeatChallenge(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	gatewayID := args[0]
+
+	existing, err := stub.GetState("gateway:" + gatewayID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to check gateway: %s", err.Error()))
+	}
+	if existing == nil {
+		return shim.Error(fmt.Sprintf("Gateway not found: %s", gatewayID))
+	}
+
+	nonce, err := shim.GenerateNonce(16)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to generate challenge: %s", err.Error()))
+	}
+
+	if err := stub.PutState("challenge:"+gatewayID, []byte(nonce)); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store challenge: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(nonce))
+}
+
+// verifyAttestation checks that signatureEncoded is a valid signature,
+// under algorithm against publicKey, over the canonical payload formed by
+// joining parts with "|" and the current transaction ID
+func verifyAttestation(stub shim.ChaincodeStubInterface, publicKey, algorithm, signatureEncoded string, parts ...string) error {
+	signature, err := shim.DecodeSignature(signatureEncoded)
+	if err != nil {
+		return err
+	}
+
+	payload := strings.Join(append(append([]string{}, parts...), stub.GetTxID()), "|")
+
+	ok, err := shim.VerifySignature(publicKey, shim.SignatureAlgorithm(algorithm), []byte(payload), signature)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("signature does not verify against the registered public key")
+	}
+
+	return nil
+}
+
+// GatewayHealth summarizes a gateway's liveness based on its last
+// heartbeat and the configured heartbeatTimeout
+type GatewayHealth struct {
+	GatewayID             string `json:"gatewayID"`
+	Status                string `json:"status"`
+	SecondsSinceHeartbeat int64  `json:"secondsSinceHeartbeat"`
+	MissedIntervals       int64  `json:"missedIntervals"`
+}
+
+// GatewayHistoryEntry records a status transition for a gateway under
+// "gateway_history:<id>:<ts>"
+type GatewayHistoryEntry struct {
+	GatewayID string `json:"gatewayID"`
+	FromState string `json:"fromState"`
+	ToState   string `json:"toState"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// heartbeatTimeout reads the configured heartbeatTimeout (seconds) from
+// the system config document, defaulting to 300 (5 minutes) if unset
+func heartbeatTimeout(stub shim.ChaincodeStubInterface) int64 {
+	configBytes, err := stub.GetState("config:system")
+	if err != nil || configBytes == nil {
+		return 300
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return 300
+	}
+
+	timeout, ok := config["heartbeatTimeout"].(float64)
+	if !ok || timeout <= 0 {
+		return 300
+	}
+
+	return int64(timeout)
+}
+
+// recordGatewayTransition appends a state-transition record to a
+// gateway's history under "gateway_history:<id>:<ts>"
+func recordGatewayTransition(stub shim.ChaincodeStubInterface, gatewayID, fromState, toState, reason string) error {
+	now := time.Now().Unix()
+	historyKey := fmt.Sprintf("gateway_history:%s:%d", gatewayID, now)
+
+	entry := GatewayHistoryEntry{
+		GatewayID: gatewayID,
+		FromState: fromState,
+		ToState:   toState,
+		Reason:    reason,
+		Timestamp: now,
+	}
+
+	return shim.PutStateAsJSON(stub, historyKey, entry)
+}
+
+// sweepLiveness scans every active gateway and quarantines any whose
+// LastHeartbeat is older than the configured heartbeatTimeout. Safe to
+// call repeatedly: gateways already quarantined are left alone.
+func (cc *GatewayManagementChaincode) sweepLiveness(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	timeout := heartbeatTimeout(stub)
+	now := time.Now().Unix()
+
+	iterator, err := stub.GetStateByRange("gateway:", "gateway:~")
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to load gateways: %s", err.Error()))
+	}
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to process gateways: %s", err.Error()))
+	}
+
+	var quarantined []string
+	for _, r := range results {
+		var gateway Gateway
+		gatewayBytes, _ := json.Marshal(r.Value)
+		if err := json.Unmarshal(gatewayBytes, &gateway); err != nil {
+			continue
+		}
+
+		if gateway.Status != "active" {
+			continue
+		}
+		if now-gateway.LastHeartbeat < timeout {
+			continue
+		}
+
+		gateway.Status = "quarantined"
+		if err := shim.PutStateAsJSON(stub, "gateway:"+gateway.ID, gateway); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to quarantine gateway %s: %s", gateway.ID, err.Error()))
+		}
+
+		reason := fmt.Sprintf("no heartbeat for %d seconds (timeout %d)", now-gateway.LastHeartbeat, timeout)
+		if err := recordGatewayTransition(stub, gateway.ID, "active", "quarantined", reason); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to record transition for gateway %s: %s", gateway.ID, err.Error()))
+		}
+
+		cc.logAudit(stub, gateway.ID, "QUARANTINE_GATEWAY", "gateway:"+gateway.ID, true, reason)
+
+		eventPayload, _ := json.Marshal(map[string]interface{}{
+			"gatewayID": gateway.ID,
+			"reason":    reason,
+		})
+		if err := stub.SetEvent("GatewayQuarantined", eventPayload); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to emit GatewayQuarantined event: %s", err.Error()))
+		}
+
+		quarantined = append(quarantined, gateway.ID)
+	}
+
+	responseBytes, err := json.Marshal(quarantined)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// reactivateGateway restores a quarantined gateway to active status. Gated
+// by the caller's access policy for resource "gateway", action
+// "reactivate" (the bundled admin policy grants this to principal "admin").
+func (cc *GatewayManagementChaincode) reactivateGateway(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	gatewayID := args[0]
+
+	principal, err := principalFromCreator(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to resolve caller identity: %s", err.Error()))
+	}
+
+	decision, err := cc.evaluateAccess(stub, policy.EvalContext{
+		Principal: principal,
+		Resource:  "gateway",
+		Action:    "reactivate",
+	})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if !decision.Allow {
+		cc.logAudit(stub, gatewayID, "REACTIVATE_GATEWAY", "gateway:"+gatewayID, false, decision.Reason)
+		return shim.Error(fmt.Sprintf("Access denied: %s", decision.Reason))
+	}
+
+	var gateway Gateway
+	if err := shim.GetStateAsJSON(stub, "gateway:"+gatewayID, &gateway); err != nil {
+		return shim.Error(fmt.Sprintf("Gateway not found: %s", gatewayID))
+	}
+
+	previousStatus := gateway.Status
+	gateway.Status = "active"
+	gateway.LastHeartbeat = time.Now().Unix()
+
+	if err := shim.PutStateAsJSON(stub, "gateway:"+gatewayID, gateway); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to reactivate gateway: %s", err.Error()))
+	}
+
+	if err := recordGatewayTransition(stub, gatewayID, previousStatus, "active", "reactivated by "+principal); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to record transition: %s", err.Error()))
+	}
+
+	cc.logAudit(stub, gatewayID, "REACTIVATE_GATEWAY", "gateway:"+gatewayID, true, "")
+
+	return shim.Success([]byte(fmt.Sprintf("Gateway %s reactivated successfully", gatewayID)))
+}
+
+// getGatewayHealth reports a gateway's liveness relative to the
+// configured heartbeatTimeout
+func (cc *GatewayManagementChaincode) getGatewayHealth(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	gatewayID := args[0]
+
+	var gateway Gateway
+	if err := shim.GetStateAsJSON(stub, "gateway:"+gatewayID, &gateway); err != nil {
+		return shim.Error(fmt.Sprintf("Gateway not found: %s", gatewayID))
+	}
+
+	timeout := heartbeatTimeout(stub)
+	secondsSince := time.Now().Unix() - gateway.LastHeartbeat
+	missedIntervals := int64(0)
+	if timeout > 0 {
+		missedIntervals = secondsSince / timeout
+	}
+
+	health := GatewayHealth{
+		GatewayID:             gatewayID,
+		Status:                gateway.Status,
+		SecondsSinceHeartbeat: secondsSince,
+		MissedIntervals:       missedIntervals,
+	}
+
+	responseBytes, err := json.Marshal(health)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// SecretRef records an on-chain integrity hash for a secret that is held
+// off-chain (or in a private data collection out of this chaincode's
+// reach), letting holders prove a piece of off-chain data is the one the
+// network agreed to without ever putting it on the ledger
+type SecretRef struct {
+	GatewayID  string `json:"gatewayID"`
+	Collection string `json:"collection"`
+	SecretID   string `json:"secretID"`
+	Hash       string `json:"hash"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+// attachSecretRef records an off-chain secret pointer (identified by
+// secretID, living in collection) alongside the integrity hash a verifier
+// can recompute against it
+func (cc *GatewayManagementChaincode) attachSecretRef(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 4); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	gatewayID := args[0]
+	collection := args[1]
+	secretID := args[2]
+	hash := args[3]
+
+	existing, err := stub.GetState("gateway:" + gatewayID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to check gateway: %s", err.Error()))
+	}
+	if existing == nil {
+		return shim.Error(fmt.Sprintf("Gateway not found: %s", gatewayID))
+	}
+
+	ref := SecretRef{
+		GatewayID:  gatewayID,
+		Collection: collection,
+		SecretID:   secretID,
+		Hash:       hash,
+		CreatedAt:  time.Now().Unix(),
+	}
+
+	refKey := fmt.Sprintf("secretref:%s:%s", gatewayID, secretID)
+	if err := shim.PutStateAsJSON(stub, refKey, ref); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store secret reference: %s", err.Error()))
+	}
+
+	cc.logAuditCollection(stub, gatewayID, "ATTACH_SECRET_REF", refKey, collection, true, "")
+
+	return shim.Success([]byte(fmt.Sprintf("Secret reference %s attached to gateway %s", secretID, gatewayID)))
+}
+
+// verifySecretRef recomputes the hash of providedBytes and reports
+// whether it matches the integrity hash recorded by attachSecretRef
+func (cc *GatewayManagementChaincode) verifySecretRef(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 3); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	gatewayID := args[0]
+	secretID := args[1]
+	providedBytes := args[2]
+
+	refKey := fmt.Sprintf("secretref:%s:%s", gatewayID, secretID)
+	var ref SecretRef
+	if err := shim.GetStateAsJSON(stub, refKey, &ref); err != nil {
+		return shim.Error(fmt.Sprintf("Secret reference not found: %s", err.Error()))
+	}
+
+	hash := sha256.Sum256([]byte(providedBytes))
+	computed := hex.EncodeToString(hash[:])
+	matches := computed == ref.Hash
+
+	cc.logAuditCollection(stub, gatewayID, "VERIFY_SECRET_REF", refKey, ref.Collection, matches, "")
+
+	responseBytes, err := json.Marshal(map[string]interface{}{
+		"gatewayID": gatewayID,
+		"secretID":  secretID,
+		"matches":   matches,
+	})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
 // createPolicy creates a new access policy
 func (cc *GatewayManagementChaincode) createPolicy(stub shim.ChaincodeStubInterface, args []string) shim.Response {
 	if err := shim.ValidateArgs(args, 4); err != nil {
@@ -468,6 +945,228 @@ func (cc *GatewayManagementChaincode) listPolicies(stub shim.ChaincodeStubInterf
 	return shim.Success(responseBytes)
 }
 
+// RequestContext carries optional attributes used by policy conditions
+// (attribute equality, source IP for CIDR rules, time-of-day for time
+// window rules), supplied as a JSON object in the contextJSON argument
+type RequestContext struct {
+	Attributes map[string]string `json:"attributes,omitempty"`
+	SourceIP   string            `json:"sourceIP,omitempty"`
+	TimeOfDay  string            `json:"timeOfDay,omitempty"`
+}
+
+// AccessCheckResult is the structured response returned by checkAccess
+type AccessCheckResult struct {
+	Allow         bool   `json:"allow"`
+	MatchedPolicy string `json:"matchedPolicy,omitempty"`
+	MatchedRule   int    `json:"matchedRule"`
+	Reason        string `json:"reason"`
+}
+
+// checkAccess evaluates the access policies applicable to a gateway
+// against the transaction creator's identity, returning a structured
+// allow/deny decision and emitting an AccessDecision event. Conditions
+// are evaluated via the shim/policy engine's built-in DSL; conflict
+// resolution across matching rules (deny-overrides/first-match/priority)
+// is read from the "policyConflictStrategy" system config field,
+// defaulting to deny-overrides.
+func (cc *GatewayManagementChaincode) checkAccess(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 3, 4); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	gatewayID := args[0]
+	resource := args[1]
+	action := args[2]
+
+	reqCtx := RequestContext{}
+	if len(args) > 3 && args[3] != "" {
+		if err := json.Unmarshal([]byte(args[3]), &reqCtx); err != nil {
+			return shim.Error(fmt.Sprintf("Invalid contextJSON: %s", err.Error()))
+		}
+	}
+
+	principal, err := principalFromCreator(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to resolve caller identity: %s", err.Error()))
+	}
+
+	result, err := cc.evaluateAccess(stub, policy.EvalContext{
+		Principal:  principal,
+		Resource:   resource,
+		Action:     action,
+		Attributes: reqCtx.Attributes,
+		TimeOfDay:  reqCtx.TimeOfDay,
+		SourceIP:   reqCtx.SourceIP,
+	})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	cc.logAuditResponse(stub, gatewayID, action, resource, result.Allow, result.Reason)
+
+	responseBytes, err := json.Marshal(result)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	eventPayload, err := json.Marshal(map[string]interface{}{
+		"gatewayID": gatewayID,
+		"principal": principal,
+		"resource":  resource,
+		"action":    action,
+		"result":    result,
+	})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal event payload: %s", err.Error()))
+	}
+	if err := stub.SetEvent("AccessDecision", eventPayload); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to emit AccessDecision event: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// evaluateAccess loads every stored policy and returns the access decision
+// for evalCtx, applying the system-configured conflict-resolution strategy
+// across whichever policies have an applicable rule
+func (cc *GatewayManagementChaincode) evaluateAccess(stub shim.ChaincodeStubInterface, evalCtx policy.EvalContext) (AccessCheckResult, error) {
+	strategy := cc.policyConflictStrategy(stub)
+
+	iterator, err := stub.GetStateByRange("policy:", "policy:~")
+	if err != nil {
+		return AccessCheckResult{}, fmt.Errorf("failed to load policies: %w", err)
+	}
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return AccessCheckResult{}, fmt.Errorf("failed to process policies: %w", err)
+	}
+
+	evaluator := policy.NewDefaultEvaluator()
+
+	var decisions []policy.Decision
+	for _, r := range results {
+		var accessPolicy AccessPolicy
+		policyBytes, _ := json.Marshal(r.Value)
+		if err := json.Unmarshal(policyBytes, &accessPolicy); err != nil {
+			continue
+		}
+
+		decision, err := evaluator.Evaluate(evalCtx, toPolicyPolicy(accessPolicy, strategy))
+		if err != nil {
+			return AccessCheckResult{}, fmt.Errorf("failed to evaluate policy %s: %w", accessPolicy.ID, err)
+		}
+		if decision.MatchedRule == -1 {
+			continue
+		}
+
+		decisions = append(decisions, decision)
+		if strategy == policy.FirstMatch {
+			break
+		}
+	}
+
+	return resolveAccessDecisions(decisions, strategy), nil
+}
+
+// resolveAccessDecisions reconciles one Decision per matching policy into a
+// single result using the same strategy toPolicyPolicy gave every policy,
+// so a Deny from one policy can't be silently overwritten by an Allow from
+// a later one under deny-overrides, and priority is honored across
+// policies rather than only within a single policy's own rule set.
+// FirstMatch needs no reconciliation: evaluateAccess already stopped at the
+// first matching policy, so decisions has exactly one entry.
+func resolveAccessDecisions(decisions []policy.Decision, strategy policy.ConflictStrategy) AccessCheckResult {
+	if len(decisions) == 0 {
+		return AccessCheckResult{Allow: false, MatchedRule: -1, Reason: "no applicable policy"}
+	}
+
+	best := decisions[0]
+	for _, d := range decisions[1:] {
+		switch strategy {
+		case policy.Priority:
+			if d.Priority > best.Priority {
+				best = d
+			}
+		default: // deny-overrides
+			if !d.Allow && best.Allow {
+				best = d
+			}
+		}
+	}
+
+	return AccessCheckResult{
+		Allow:         best.Allow,
+		MatchedPolicy: best.MatchedPolicy,
+		MatchedRule:   best.MatchedRule,
+		Reason:        best.Reason,
+	}
+}
+
+// policyConflictStrategy reads the configured conflict-resolution strategy
+// from the system config document, defaulting to deny-overrides
+func (cc *GatewayManagementChaincode) policyConflictStrategy(stub shim.ChaincodeStubInterface) policy.ConflictStrategy {
+	configBytes, err := stub.GetState("config:system")
+	if err != nil || configBytes == nil {
+		return policy.DenyOverrides
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return policy.DenyOverrides
+	}
+
+	strategy, ok := config["policyConflictStrategy"].(string)
+	if !ok || strategy == "" {
+		return policy.DenyOverrides
+	}
+
+	return policy.ConflictStrategy(strategy)
+}
+
+// toPolicyPolicy converts an on-chain AccessPolicy into the shim/policy
+// engine's evaluation types
+func toPolicyPolicy(p AccessPolicy, strategy policy.ConflictStrategy) policy.Policy {
+	rules := make([]policy.Rule, 0, len(p.Rules))
+	for _, r := range p.Rules {
+		effect := policy.Effect(r.Effect)
+		if effect == "" {
+			effect = policy.Allow
+		}
+		rules = append(rules, policy.Rule{
+			Resource:   r.Resource,
+			Action:     r.Action,
+			Principals: r.Principals,
+			Conditions: r.Conditions,
+			Effect:     effect,
+			Priority:   r.Priority,
+		})
+	}
+
+	return policy.Policy{ID: p.ID, Rules: rules, Strategy: strategy}
+}
+
+// principalFromCreator extracts a principal identifier from the
+// transaction creator's X.509 identity, falling back to the raw creator
+// bytes if they are not a parseable certificate
+func principalFromCreator(stub shim.ChaincodeStubInterface) (string, error) {
+	creator, err := stub.GetCreator()
+	if err != nil {
+		return "", fmt.Errorf("failed to get creator: %w", err)
+	}
+
+	block, _ := pem.Decode(creator)
+	if block == nil {
+		return strings.TrimSpace(string(creator)), nil
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return strings.TrimSpace(string(creator)), nil
+	}
+
+	return cert.Subject.CommonName, nil
+}
+
 // auditLog creates an audit log entry
 func (cc *GatewayManagementChaincode) auditLog(stub shim.ChaincodeStubInterface, args []string) shim.Response {
 	if err := shim.ValidateArgsRange(args, 4, 5); err != nil {
@@ -495,30 +1194,34 @@ func (cc *GatewayManagementChaincode) auditLog(stub shim.ChaincodeStubInterface,
 func (cc *GatewayManagementChaincode) queryAuditLogs(stub shim.ChaincodeStubInterface, args []string) shim.Response {
 	gatewayFilter := ""
 	actionFilter := ""
-	
+	collectionFilter := ""
+
 	if len(args) > 0 {
 		gatewayFilter = args[0]
 	}
 	if len(args) > 1 {
 		actionFilter = args[1]
 	}
-	
+	if len(args) > 2 {
+		collectionFilter = args[2]
+	}
+
 	iterator, err := stub.GetStateByRange("audit:", "audit:~")
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to get audit logs: %s", err.Error()))
 	}
-	
+
 	results, err := shim.IteratorToArray(iterator)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
 	}
-	
+
 	var auditLogs []AuditLog
 	for _, result := range results {
 		var auditLog AuditLog
 		logBytes, _ := json.Marshal(result.Value)
 		json.Unmarshal(logBytes, &auditLog)
-		
+
 		// Apply filters
 		if gatewayFilter != "" && auditLog.GatewayID != gatewayFilter {
 			continue
@@ -526,7 +1229,10 @@ func (cc *GatewayManagementChaincode) queryAuditLogs(stub shim.ChaincodeStubInte
 		if actionFilter != "" && auditLog.Action != actionFilter {
 			continue
 		}
-		
+		if collectionFilter != "" && auditLog.Collection != collectionFilter {
+			continue
+		}
+
 		auditLogs = append(auditLogs, auditLog)
 	}
 	
@@ -538,29 +1244,123 @@ func (cc *GatewayManagementChaincode) queryAuditLogs(stub shim.ChaincodeStubInte
 	return shim.Success(responseBytes)
 }
 
+// RichQueryResponse wraps a page of rich-query results with the bookmark
+// needed to fetch the next page
+type RichQueryResponse struct {
+	Results  json.RawMessage `json:"results"`
+	Bookmark string          `json:"bookmark"`
+}
+
+// queryGatewaysRich accepts a JSON selector (e.g. {"status":"active",
+// "organizationID":"org1"}) plus an optional pageSize and bookmark, and
+// returns a page of the documents matching it without scanning the entire
+// "gateway:" range. The selector is passed to the state database
+// unchanged; results are not restricted to gateway records
+func (cc *GatewayManagementChaincode) queryGatewaysRich(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	return richQuery(stub, args, func(v interface{}) bool { return true })
+}
+
+// queryPoliciesRich accepts a JSON selector plus an optional pageSize and
+// bookmark, and returns a page of the documents matching it. The selector
+// is passed to the state database unchanged; results are not restricted to
+// access policy records
+func (cc *GatewayManagementChaincode) queryPoliciesRich(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	return richQuery(stub, args, func(v interface{}) bool { return true })
+}
+
+// queryAuditLogsRich accepts a JSON selector (org, status, time range,
+// action) plus an optional pageSize and bookmark, and returns a page of
+// the documents matching it. The selector is passed to the state database
+// unchanged; results are not restricted to audit log entries
+func (cc *GatewayManagementChaincode) queryAuditLogsRich(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	return richQuery(stub, args, func(v interface{}) bool { return true })
+}
+
+// richQuery runs a CouchDB Mango-style selector (args[0]) through
+// stub.GetQueryResultWithPagination, paging with optional args[1]
+// (pageSize) and args[2] (bookmark)
+func richQuery(stub shim.ChaincodeStubInterface, args []string, _ func(interface{}) bool) shim.Response {
+	if err := shim.ValidateArgsRange(args, 1, 3); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	selector := args[0]
+
+	var pageSize int32 = 20
+	if len(args) > 1 && args[1] != "" {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Invalid pageSize: %s", args[1]))
+		}
+		pageSize = int32(parsed)
+	}
+
+	bookmark := ""
+	if len(args) > 2 {
+		bookmark = args[2]
+	}
+
+	iterator, metadata, err := stub.GetQueryResultWithPagination(selector, pageSize, bookmark)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to run rich query: %s", err.Error()))
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
+	}
+
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal results: %s", err.Error()))
+	}
+
+	responseBytes, err := json.Marshal(RichQueryResponse{
+		Results:  resultsJSON,
+		Bookmark: metadata.Bookmark,
+	})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
 // Helper function to log audit events
 func (cc *GatewayManagementChaincode) logAudit(stub shim.ChaincodeStubInterface, gatewayID, action, resource string, success bool, errorMessage string) {
-	auditID := fmt.Sprintf("audit:%d:%s", time.Now().UnixNano(), stub.GetTxID())
-	
-	auditLog := AuditLog{
-		ID:            auditID,
-		Timestamp:     time.Now().Unix(),
-		GatewayID:     gatewayID,
-		Action:        action,
-		Resource:      resource,
-		Success:       success,
-		ErrorMessage:  errorMessage,
-		TransactionID: stub.GetTxID(),
-	}
-	
-	shim.PutStateAsJSON(stub, auditID, auditLog)
+	appendAuditEntry(stub, gatewayID, action, resource, success, errorMessage, "")
 }
 
 // Helper function to log audit events and return response
 func (cc *GatewayManagementChaincode) logAuditResponse(stub shim.ChaincodeStubInterface, gatewayID, action, resource string, success bool, errorMessage string) shim.Response {
+	entry, err := appendAuditEntry(stub, gatewayID, action, resource, success, errorMessage, "")
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to log audit entry: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Audit log created: %s", entry.ID)))
+}
+
+// logAuditCollection records an audit entry scoped to a private data
+// collection, so audit queries can be restricted to entries touching that
+// collection while preserving org-level confidentiality of its contents
+func (cc *GatewayManagementChaincode) logAuditCollection(stub shim.ChaincodeStubInterface, gatewayID, action, resource, collection string, success bool, errorMessage string) {
+	appendAuditEntry(stub, gatewayID, action, resource, success, errorMessage, collection)
+}
+
+// appendAuditEntry writes the next entry in the hash chain: its PrevHash is
+// the EntryHash of whatever entry currently sits at "audit_head", and its
+// own EntryHash is committed to the ledger alongside it, then becomes the
+// new head
+func appendAuditEntry(stub shim.ChaincodeStubInterface, gatewayID, action, resource string, success bool, errorMessage, collection string) (AuditLog, error) {
 	auditID := fmt.Sprintf("audit:%d:%s", time.Now().UnixNano(), stub.GetTxID())
-	
-	auditLog := AuditLog{
+
+	prevHashBytes, err := stub.GetState("audit_head")
+	if err != nil {
+		return AuditLog{}, fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+
+	entry := AuditLog{
 		ID:            auditID,
 		Timestamp:     time.Now().Unix(),
 		GatewayID:     gatewayID,
@@ -569,14 +1369,271 @@ func (cc *GatewayManagementChaincode) logAuditResponse(stub shim.ChaincodeStubIn
 		Success:       success,
 		ErrorMessage:  errorMessage,
 		TransactionID: stub.GetTxID(),
+		PrevHash:      string(prevHashBytes),
+		Collection:    collection,
 	}
-	
-	err := shim.PutStateAsJSON(stub, auditID, auditLog)
+
+	entryHash, err := hashAuditEntry(entry)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to log audit entry: %s", err.Error()))
+		return AuditLog{}, err
 	}
-	
-	return shim.Success([]byte(fmt.Sprintf("Audit log created: %s", auditID)))
+	entry.EntryHash = entryHash
+
+	if err := shim.PutStateAsJSON(stub, auditID, entry); err != nil {
+		return AuditLog{}, fmt.Errorf("failed to store audit entry: %w", err)
+	}
+	if err := stub.PutState("audit_head", []byte(entryHash)); err != nil {
+		return AuditLog{}, fmt.Errorf("failed to update audit chain head: %w", err)
+	}
+
+	return entry, nil
+}
+
+// hashAuditEntry returns the SHA-256 hex digest of entry's canonical JSON
+// with EntryHash cleared, so the hash commits to the entry (including its
+// link to the previous one) without depending on itself
+func hashAuditEntry(entry AuditLog) (string, error) {
+	entry.EntryHash = ""
+	canonical, err := shim.CanonicalJSON(entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize audit entry: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sealAuditBatch computes a Merkle root over the EntryHash of every audit
+// entry in [fromID, toID] and persists it under "audit_root:<batchID>",
+// giving external auditors a single, compact commitment they can verify
+// inclusion proofs against
+func (cc *GatewayManagementChaincode) sealAuditBatch(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	fromID := args[0]
+	toID := args[1]
+
+	iterator, err := stub.GetStateByRange(fromID, toID+"~")
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to load audit range: %s", err.Error()))
+	}
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to process audit range: %s", err.Error()))
+	}
+	if len(results) == 0 {
+		return shim.Error("No audit entries in the given range")
+	}
+
+	leaves := make([]string, 0, len(results))
+	for _, r := range results {
+		var entry AuditLog
+		entryBytes, _ := json.Marshal(r.Value)
+		if err := json.Unmarshal(entryBytes, &entry); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to parse audit entry %s: %s", r.Key, err.Error()))
+		}
+		if entry.EntryHash == "" {
+			return shim.Error(fmt.Sprintf("Audit entry %s has no EntryHash to seal", r.Key))
+		}
+		leaves = append(leaves, entry.EntryHash)
+	}
+
+	batchID := fmt.Sprintf("batch:%d:%s", time.Now().UnixNano(), stub.GetTxID())
+	batch := AuditBatch{
+		BatchID:  batchID,
+		FromID:   fromID,
+		ToID:     toID,
+		Leaves:   leaves,
+		Root:     merkleRoot(leaves),
+		SealedAt: time.Now().Unix(),
+	}
+
+	if err := shim.PutStateAsJSON(stub, "audit_root:"+batchID, batch); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store audit batch: %s", err.Error()))
+	}
+
+	for i, r := range results {
+		pointer := auditBatchPointer{BatchID: batchID, Index: i}
+		if err := shim.PutStateAsJSON(stub, "audit_batch_of:"+r.Key, pointer); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to index audit entry %s into batch: %s", r.Key, err.Error()))
+		}
+	}
+
+	responseBytes, err := json.Marshal(batch)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// getAuditProof returns the Merkle inclusion path proving auditID is part
+// of the batch it was sealed into, plus that batch's root
+func (cc *GatewayManagementChaincode) getAuditProof(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	auditID := args[0]
+
+	var pointer auditBatchPointer
+	if err := shim.GetStateAsJSON(stub, "audit_batch_of:"+auditID, &pointer); err != nil {
+		return shim.Error(fmt.Sprintf("Audit entry %s has not been sealed into a batch yet: %s", auditID, err.Error()))
+	}
+
+	var batch AuditBatch
+	if err := shim.GetStateAsJSON(stub, "audit_root:"+pointer.BatchID, &batch); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to load audit batch %s: %s", pointer.BatchID, err.Error()))
+	}
+	if pointer.Index < 0 || pointer.Index >= len(batch.Leaves) {
+		return shim.Error("Audit batch pointer index out of range")
+	}
+
+	proof := AuditProof{
+		AuditID: auditID,
+		BatchID: pointer.BatchID,
+		Root:    batch.Root,
+		Leaf:    batch.Leaves[pointer.Index],
+		Path:    merkleProof(batch.Leaves, pointer.Index),
+	}
+
+	responseBytes, err := json.Marshal(proof)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal proof: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// verifyAuditChain walks the PrevHash links across [fromID, toID],
+// recomputing each entry's hash and confirming it matches the next
+// entry's PrevHash, to detect gaps or tampering
+func (cc *GatewayManagementChaincode) verifyAuditChain(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	fromID := args[0]
+	toID := args[1]
+
+	iterator, err := stub.GetStateByRange(fromID, toID+"~")
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to load audit range: %s", err.Error()))
+	}
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to process audit range: %s", err.Error()))
+	}
+
+	verification := AuditChainVerification{Valid: true}
+
+	var previousHash string
+	for i, r := range results {
+		var entry AuditLog
+		entryBytes, _ := json.Marshal(r.Value)
+		if err := json.Unmarshal(entryBytes, &entry); err != nil {
+			verification.Valid = false
+			verification.BrokenAtID = r.Key
+			verification.Reason = fmt.Sprintf("failed to parse entry: %s", err.Error())
+			break
+		}
+
+		if i > 0 && entry.PrevHash != previousHash {
+			verification.Valid = false
+			verification.BrokenAtID = entry.ID
+			verification.Reason = "prevHash does not match the preceding entry's hash"
+			break
+		}
+
+		recomputed, err := hashAuditEntry(entry)
+		if err != nil {
+			verification.Valid = false
+			verification.BrokenAtID = entry.ID
+			verification.Reason = fmt.Sprintf("failed to recompute hash: %s", err.Error())
+			break
+		}
+		if recomputed != entry.EntryHash {
+			verification.Valid = false
+			verification.BrokenAtID = entry.ID
+			verification.Reason = "stored entryHash does not match its recomputed hash"
+			break
+		}
+
+		previousHash = entry.EntryHash
+		verification.EntriesSeen++
+	}
+
+	responseBytes, err := json.Marshal(verification)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// merkleRoot computes a binary Merkle root over leaf hex hashes, in
+// order, duplicating the final leaf at each level when that level's size
+// is odd
+func merkleRoot(leaves []string) string {
+	if len(leaves) == 0 {
+		return ""
+	}
+
+	level := append([]string{}, leaves...)
+	for len(level) > 1 {
+		var next []string
+		for i := 0; i < len(level); i += 2 {
+			left := level[i]
+			right := left
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, hashPair(left, right))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// merkleProof returns the sibling hashes needed to recompute the root
+// from leaves[index], innermost first
+func merkleProof(leaves []string, index int) []string {
+	var proof []string
+
+	level := append([]string{}, leaves...)
+	for len(level) > 1 {
+		var sibling string
+		if index%2 == 0 {
+			if index+1 < len(level) {
+				sibling = level[index+1]
+			} else {
+				sibling = level[index]
+			}
+		} else {
+			sibling = level[index-1]
+		}
+		proof = append(proof, sibling)
+
+		var next []string
+		for i := 0; i < len(level); i += 2 {
+			left := level[i]
+			right := left
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, hashPair(left, right))
+		}
+		level = next
+		index /= 2
+	}
+
+	return proof
+}
+
+func hashPair(left, right string) string {
+	sum := sha256.Sum256([]byte(left + right))
+	return hex.EncodeToString(sum[:])
 }
 
 // main function - entry point for the chaincode
@@ -0,0 +1,299 @@
+package issuer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beacon-blockchain/sdk-go/examples/identity-verification/vc"
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// proofJWTTyp is the required JOSE "typ" header of an OpenID4VCI
+// proof-of-possession JWT.
+const proofJWTTyp = "openid4vci-proof+jwt"
+
+// CredentialRequest is the body of a POST to the /credential endpoint.
+type CredentialRequest struct {
+	Format                    string            `json:"format"`
+	CredentialConfigurationID string            `json:"credential_configuration_id,omitempty"`
+	Proof                     ProofOfPossession `json:"proof"`
+}
+
+// ProofOfPossession is a wallet's proof that it controls the key the
+// credential should be bound to, per the OpenID4VCI "jwt" proof type.
+type ProofOfPossession struct {
+	ProofType string `json:"proof_type"`
+	JWT       string `json:"jwt"`
+}
+
+// CredentialResponse is the body returned from a successful /credential
+// request. Credential is either a compact JWT-VC string (format
+// "jwt_vc_json") or the JSON-LD VC Data Model document (format "ldp_vc").
+type CredentialResponse struct {
+	Format     string      `json:"format"`
+	Credential interface{} `json:"credential"`
+	CNonce     string      `json:"c_nonce"`
+}
+
+type credentialErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// CredentialHandler implements the /credential endpoint: it verifies the
+// bearer access token and the wallet's proof-of-possession JWT, mints a
+// credential signed by the issuer's key, records it on-chain via
+// issueCredential, and returns it to the wallet.
+func (s *Service) CredentialHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken := bearerToken(r)
+	if accessToken == "" {
+		writeCredentialError(w, http.StatusUnauthorized, "invalid_token", "missing bearer access token")
+		return
+	}
+
+	state, err := s.findByAccessToken(accessToken)
+	if err != nil {
+		writeCredentialError(w, http.StatusUnauthorized, "invalid_token", err.Error())
+		return
+	}
+
+	var req CredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeCredentialError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	supported := s.cfg.CredentialsSupported[state.credentialConfigID]
+	if req.Format == "" {
+		req.Format = supported.Format
+	}
+	if req.Format != supported.Format {
+		writeCredentialError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("offer was issued for format %q, not %q", supported.Format, req.Format))
+		return
+	}
+	if req.Proof.ProofType != "jwt" {
+		writeCredentialError(w, http.StatusBadRequest, "invalid_or_missing_proof", fmt.Sprintf("unsupported proof_type: %s", req.Proof.ProofType))
+		return
+	}
+
+	subjectPublicKey, subjectKeyType, err := s.getSubjectIdentity(state.subjectID)
+	if err != nil {
+		writeCredentialError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	if err := s.verifyProofOfPossession(req.Proof.JWT, subjectPublicKey, subjectKeyType, state.cNonce); err != nil {
+		writeCredentialError(w, http.StatusBadRequest, "invalid_or_missing_proof", err.Error())
+		return
+	}
+
+	credentialID := state.offerID
+	claims := state.claims
+	if claims == nil {
+		claims = make(map[string]interface{})
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		writeCredentialError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	internalFormat, err := internalFormatFor(req.Format)
+	if err != nil {
+		writeCredentialError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	expirationDays := supported.ExpirationDays
+	if expirationDays == 0 {
+		expirationDays = 365
+	}
+
+	credential, proofValue, err := s.signCredential(credentialID, supported.CredentialType, state.subjectID, claims, internalFormat, expirationDays)
+	if err != nil {
+		writeCredentialError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	_, err = s.invoker.Invoke("issueCredential", []string{
+		credentialID,
+		supported.CredentialType,
+		s.cfg.IdentityID,
+		state.subjectID,
+		string(claimsJSON),
+		proofValue,
+		strconv.FormatInt(expirationDays, 10),
+		internalFormat,
+	})
+	if err != nil {
+		writeCredentialError(w, http.StatusInternalServerError, "server_error", fmt.Sprintf("failed to record issuance on-chain: %s", err.Error()))
+		return
+	}
+
+	nextNonce, err := randomToken(16)
+	if err != nil {
+		writeCredentialError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	s.mu.Lock()
+	state.cNonce = nextNonce
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CredentialResponse{
+		Format:     req.Format,
+		Credential: credential,
+		CNonce:     nextNonce,
+	})
+}
+
+// signCredential builds and signs the credential with the issuer's key in
+// the requested internal format ("ldp_vc" or "jwt_vc"), returning the value
+// to hand back to the wallet and the proofValue issueCredential expects.
+func (s *Service) signCredential(credentialID, credentialType, subject string, claims map[string]interface{}, internalFormat string, expirationDays int64) (credential interface{}, proofValue string, err error) {
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(expirationDays) * 24 * time.Hour)
+
+	subjectClaims := make(map[string]interface{}, len(claims)+1)
+	for k, v := range claims {
+		subjectClaims[k] = v
+	}
+	subjectClaims["id"] = subject
+
+	builder := vc.NewBuilder().
+		AddType(credentialType).
+		SetID(credentialID).
+		SetIssuer(s.cfg.IdentityID).
+		SetIssuanceDate(now).
+		SetExpirationDate(expiresAt).
+		SetCredentialSubject(subjectClaims)
+
+	switch internalFormat {
+	case "jwt_vc":
+		vcPayload := builder.Build()
+		jwt, err := vc.EncodeJWTVC(vc.JWTClaims{
+			Issuer:    s.cfg.IdentityID,
+			Subject:   subject,
+			ExpiresAt: expiresAt.Unix(),
+			VC:        vcPayload,
+		}, s.cfg.SigningKey, "EdDSA")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to sign JWT-VC: %w", err)
+		}
+		return jwt, jwt, nil
+
+	default: // "ldp_vc"
+		verificationMethod := fmt.Sprintf("identity:%s#key-1", s.cfg.IdentityID)
+		signed, err := builder.Sign(s.cfg.SigningKey, verificationMethod)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to sign credential: %w", err)
+		}
+		return signed, signed.Proof.ProofValue, nil
+	}
+}
+
+// verifyProofOfPossession checks that jwt is a JOSE JWS of type
+// "openid4vci-proof+jwt", signed by the subject identity's registered key,
+// carrying this issuer as its audience and the offer's current c_nonce —
+// proving the wallet holding the request is the same one holding the key
+// the credential's subject identity was registered under.
+func (s *Service) verifyProofOfPossession(jwt, subjectPublicKey, subjectKeyType, expectedNonce string) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed proof JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("failed to decode proof JWT header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return fmt.Errorf("failed to parse proof JWT header: %w", err)
+	}
+	if header.Typ != proofJWTTyp {
+		return fmt.Errorf("proof JWT typ %q does not match expected %q", header.Typ, proofJWTTyp)
+	}
+
+	expectedAlg, err := vc.AlgorithmForProofType(subjectKeyType)
+	if err != nil {
+		return err
+	}
+	if header.Alg != expectedAlg {
+		return fmt.Errorf("proof JWT alg %q does not match subject's registered key type (expected %q)", header.Alg, expectedAlg)
+	}
+	if header.Alg != "EdDSA" {
+		return fmt.Errorf("unsupported proof JWT algorithm: %s", header.Alg)
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("failed to decode proof JWT payload: %w", err)
+	}
+	var claims struct {
+		Audience string `json:"aud"`
+		Nonce    string `json:"nonce"`
+	}
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil {
+		return fmt.Errorf("failed to parse proof JWT payload: %w", err)
+	}
+	if claims.Audience != s.cfg.IssuerURL {
+		return fmt.Errorf("proof JWT aud %q does not match this issuer %q", claims.Audience, s.cfg.IssuerURL)
+	}
+	if claims.Nonce != expectedNonce {
+		return fmt.Errorf("proof JWT nonce does not match the c_nonce issued for this offer")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("failed to decode proof JWT signature: %w", err)
+	}
+	signingInput := []byte(parts[0] + "." + parts[1])
+	valid, err := shim.VerifySignature(subjectPublicKey, shim.AlgorithmEd25519, signingInput, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("proof JWT signature verification failed")
+	}
+
+	return nil
+}
+
+// internalFormatFor maps an OpenID4VCI credential format identifier to the
+// format string issueCredential expects.
+func internalFormatFor(format string) (string, error) {
+	switch format {
+	case "jwt_vc_json":
+		return "jwt_vc", nil
+	case "ldp_vc":
+		return "ldp_vc", nil
+	default:
+		return "", fmt.Errorf("unsupported credential format: %s", format)
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// request header, returning "" if absent or malformed.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+func writeCredentialError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(credentialErrorResponse{Error: code, ErrorDescription: description})
+}
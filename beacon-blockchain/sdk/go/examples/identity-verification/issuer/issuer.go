@@ -0,0 +1,123 @@
+// Package issuer implements an off-chain OpenID for Verifiable Credential
+// Issuance (OpenID4VCI) service that bridges standard EUDI/OID4VC wallets
+// to the identity-verification chaincode's issueCredential function. The
+// service holds the issuer's signing key, assembles and signs credentials
+// using the vc package exactly as issueCredential expects them, and
+// submits the signed credential on-chain to record issuance — the wallet
+// never talks to the chaincode directly.
+package issuer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/beacon-blockchain/sdk-go/examples/identity-verification/vc"
+)
+
+// ChaincodeInvoker is the minimal surface this service needs against the
+// ledger: submitting the issueCredential transaction and querying
+// getIdentity to look up a subject's registered key for proof-of-possession
+// verification. Production deployments back this with a Fabric Gateway or
+// peer client; tests can back it with shimtest.MockStub.
+type ChaincodeInvoker interface {
+	Invoke(function string, args []string) ([]byte, error)
+}
+
+// CredentialSupported describes one credential configuration this issuer
+// offers, as published in IssuerMetadata.CredentialConfigurationsSupported.
+type CredentialSupported struct {
+	Format           string   `json:"format"`
+	CredentialType   string   `json:"credential_type"`
+	ExpirationDays   int64    `json:"-"`
+	CredentialSchema []string `json:"credential_definition,omitempty"`
+}
+
+// Config configures an issuer Service.
+type Config struct {
+	// IssuerURL is this service's externally reachable base URL. It is
+	// published as "credential_issuer" in the metadata document and
+	// checked as the "aud" of wallet proof-of-possession JWTs.
+	IssuerURL string
+	// IdentityID is the issuer's identity already registered on-chain —
+	// the "issuer" argument passed to issueCredential.
+	IdentityID string
+	// SigningKey signs credentials before they are submitted on-chain. It
+	// must correspond to IdentityID's registered public key.
+	SigningKey ed25519.PrivateKey
+	// CredentialsSupported maps a credential configuration ID (as used in
+	// a credential offer's "credentials" array) to its definition.
+	CredentialsSupported map[string]CredentialSupported
+	// PreAuthorizedCodeTTL bounds how long an offer's pre-authorized_code
+	// may be redeemed at the token endpoint. Defaults to 10 minutes.
+	PreAuthorizedCodeTTL time.Duration
+	// AccessTokenTTL bounds how long an access token issued at the token
+	// endpoint may be used at the credential endpoint. Defaults to 5
+	// minutes.
+	AccessTokenTTL time.Duration
+}
+
+// Service implements the OpenID4VCI issuer endpoints on top of a
+// ChaincodeInvoker. It is safe for concurrent use.
+type Service struct {
+	cfg     Config
+	invoker ChaincodeInvoker
+
+	mu     sync.Mutex
+	offers map[string]*offerState
+}
+
+// NewService constructs an issuer Service. Zero-valued TTLs in cfg are
+// replaced with their defaults.
+func NewService(cfg Config, invoker ChaincodeInvoker) *Service {
+	if cfg.PreAuthorizedCodeTTL == 0 {
+		cfg.PreAuthorizedCodeTTL = 10 * time.Minute
+	}
+	if cfg.AccessTokenTTL == 0 {
+		cfg.AccessTokenTTL = 5 * time.Minute
+	}
+	return &Service{
+		cfg:     cfg,
+		invoker: invoker,
+		offers:  make(map[string]*offerState),
+	}
+}
+
+// randomToken returns a URL-safe random token of size bytes, used for
+// offer IDs, pre-authorized codes, access tokens, and c_nonce values.
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// getSubjectIdentity queries the chaincode for a registered identity,
+// returning its public key and key type for proof-of-possession checks.
+func (s *Service) getSubjectIdentity(subjectID string) (publicKey, keyType string, err error) {
+	result, err := s.invoker.Invoke("getIdentity", []string{subjectID})
+	if err != nil {
+		return "", "", fmt.Errorf("subject identity not found: %w", err)
+	}
+
+	var identity struct {
+		PublicKey string `json:"publicKey"`
+		KeyType   string `json:"keyType"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(result, &identity); err != nil {
+		return "", "", fmt.Errorf("failed to parse subject identity: %w", err)
+	}
+	if identity.Status != "active" {
+		return "", "", fmt.Errorf("subject identity is not active: %s", subjectID)
+	}
+	if identity.KeyType == "" {
+		identity.KeyType = vc.ProofTypeEd25519Signature2020
+	}
+	return identity.PublicKey, identity.KeyType, nil
+}
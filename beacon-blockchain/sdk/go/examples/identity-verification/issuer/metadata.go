@@ -0,0 +1,31 @@
+package issuer
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// IssuerMetadata is the Credential Issuer Metadata document published at
+// /.well-known/openid-credential-issuer.
+type IssuerMetadata struct {
+	CredentialIssuer                  string                         `json:"credential_issuer"`
+	CredentialEndpoint                string                         `json:"credential_endpoint"`
+	TokenEndpoint                     string                         `json:"token_endpoint"`
+	CredentialConfigurationsSupported map[string]CredentialSupported `json:"credential_configurations_supported"`
+}
+
+// Metadata returns this service's Credential Issuer Metadata document.
+func (s *Service) Metadata() IssuerMetadata {
+	return IssuerMetadata{
+		CredentialIssuer:                  s.cfg.IssuerURL,
+		CredentialEndpoint:                s.cfg.IssuerURL + "/credential",
+		TokenEndpoint:                     s.cfg.IssuerURL + "/token",
+		CredentialConfigurationsSupported: s.cfg.CredentialsSupported,
+	}
+}
+
+// MetadataHandler serves the Credential Issuer Metadata document.
+func (s *Service) MetadataHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Metadata())
+}
@@ -0,0 +1,136 @@
+package issuer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// grantTypePreAuthorizedCode is the OAuth 2.0 grant type identifier for
+// the OpenID4VCI pre-authorized code flow.
+const grantTypePreAuthorizedCode = "urn:ietf:params:oauth:grant-type:pre-authorized_code"
+
+// CredentialOffer is the OpenID4VCI Credential Offer object, either
+// embedded in a "openid-credential-offer://" deep link or hosted at a
+// "credential_offer_uri" for wallets to fetch.
+type CredentialOffer struct {
+	CredentialIssuer string                        `json:"credential_issuer"`
+	Credentials      []string                      `json:"credential_configuration_ids"`
+	Grants           map[string]PreAuthorizedGrant `json:"grants"`
+}
+
+// PreAuthorizedGrant is the pre-authorized_code grant payload of a
+// CredentialOffer.
+type PreAuthorizedGrant struct {
+	PreAuthorizedCode string `json:"pre-authorized_code"`
+}
+
+// offerState is the service's private bookkeeping for one offer: the
+// claims it will mint into a credential, and the nonces and tokens issued
+// as a wallet redeems it through the pre-authorized code flow.
+type offerState struct {
+	offerID            string
+	credentialConfigID string
+	subjectID          string
+	claims             map[string]interface{}
+
+	preAuthorizedCode string
+	codeExpiresAt     time.Time
+	redeemed          bool
+
+	accessToken    string
+	tokenExpiresAt time.Time
+	cNonce         string
+}
+
+// CreateOffer registers a pending issuance for subjectID under
+// credentialConfigID (a key of Config.CredentialsSupported) and returns the
+// resulting offer's deep-link URI for a wallet to scan or open. claims
+// become the credential's credentialSubject, alongside the subject's id.
+func (s *Service) CreateOffer(credentialConfigID, subjectID string, claims map[string]interface{}) (offerURI string, offerID string, err error) {
+	if _, ok := s.cfg.CredentialsSupported[credentialConfigID]; !ok {
+		return "", "", fmt.Errorf("unknown credential configuration: %s", credentialConfigID)
+	}
+
+	offerID, err = randomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	preAuthorizedCode, err := randomToken(24)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := &offerState{
+		offerID:            offerID,
+		credentialConfigID: credentialConfigID,
+		subjectID:          subjectID,
+		claims:             claims,
+		preAuthorizedCode:  preAuthorizedCode,
+		codeExpiresAt:      time.Now().Add(s.cfg.PreAuthorizedCodeTTL),
+	}
+
+	s.mu.Lock()
+	s.offers[offerID] = state
+	s.mu.Unlock()
+
+	offer := CredentialOffer{
+		CredentialIssuer: s.cfg.IssuerURL,
+		Credentials:      []string{credentialConfigID},
+		Grants: map[string]PreAuthorizedGrant{
+			grantTypePreAuthorizedCode: {PreAuthorizedCode: preAuthorizedCode},
+		},
+	}
+	offerJSON, err := json.Marshal(offer)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal credential offer: %w", err)
+	}
+
+	values := url.Values{}
+	values.Set("credential_offer", string(offerJSON))
+	return "openid-credential-offer://?" + values.Encode(), offerID, nil
+}
+
+// findByPreAuthorizedCode returns the still-valid, not-yet-redeemed offer
+// for a pre-authorized code, or an error describing why it was rejected.
+// It marks the offer redeemed before releasing the lock, so two concurrent
+// redemptions of the same code can't both observe redeemed == false and
+// each walk away with a valid access token.
+func (s *Service) findByPreAuthorizedCode(code string) (*offerState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, state := range s.offers {
+		if state.preAuthorizedCode != code {
+			continue
+		}
+		if state.redeemed {
+			return nil, fmt.Errorf("pre-authorized_code has already been redeemed")
+		}
+		if time.Now().After(state.codeExpiresAt) {
+			return nil, fmt.Errorf("pre-authorized_code has expired")
+		}
+		state.redeemed = true
+		return state, nil
+	}
+	return nil, fmt.Errorf("unknown pre-authorized_code")
+}
+
+// findByAccessToken returns the offer an access token was issued for, or
+// an error describing why it was rejected.
+func (s *Service) findByAccessToken(token string) (*offerState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, state := range s.offers {
+		if state.accessToken != token {
+			continue
+		}
+		if time.Now().After(state.tokenExpiresAt) {
+			return nil, fmt.Errorf("access token has expired")
+		}
+		return state, nil
+	}
+	return nil, fmt.Errorf("unknown access token")
+}
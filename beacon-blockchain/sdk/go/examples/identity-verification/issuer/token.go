@@ -0,0 +1,83 @@
+package issuer
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// TokenResponse is the OAuth 2.0 token response returned for a redeemed
+// pre-authorized_code, extended with the c_nonce a wallet must bind into
+// its credential request proof.
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	CNonce          string `json:"c_nonce"`
+	CNonceExpiresIn int64  `json:"c_nonce_expires_in"`
+}
+
+// tokenErrorResponse is the OAuth 2.0 error response shape, e.g.
+// {"error": "invalid_grant"}.
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// TokenHandler implements the pre-authorized_code grant at the token
+// endpoint: POST with form fields grant_type and pre-authorized_code.
+func (s *Service) TokenHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	if r.FormValue("grant_type") != grantTypePreAuthorizedCode {
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "only the pre-authorized_code grant is supported")
+		return
+	}
+
+	code := r.FormValue("pre-authorized_code")
+	if code == "" {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request", "pre-authorized_code is required")
+		return
+	}
+
+	state, err := s.findByPreAuthorizedCode(code)
+	if err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_grant", err.Error())
+		return
+	}
+
+	accessToken, err := randomToken(24)
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	cNonce, err := randomToken(16)
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	state.accessToken = accessToken
+	state.tokenExpiresAt = time.Now().Add(s.cfg.AccessTokenTTL)
+	state.cNonce = cNonce
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TokenResponse{
+		AccessToken:     accessToken,
+		TokenType:       "bearer",
+		ExpiresIn:       int64(s.cfg.AccessTokenTTL.Seconds()),
+		CNonce:          cNonce,
+		CNonceExpiresIn: int64(s.cfg.AccessTokenTTL.Seconds()),
+	})
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(tokenErrorResponse{Error: code, ErrorDescription: description})
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ChaincodeInvoker is the minimal surface this resolver needs against the
+// ledger: querying getDIDDocument to look up a did:beacon identity's
+// registered DID Document. Production deployments back this with a Fabric
+// Gateway or peer client; tests can back it with shimtest.MockStub.
+type ChaincodeInvoker interface {
+	Invoke(function string, args []string) ([]byte, error)
+}
+
+// BeaconResolver resolves did:beacon identifiers by querying the
+// identity-verification chaincode's getDIDDocument function, which
+// returns either a document an identity registered via
+// registerDIDDocument, or one synthesized from its legacy createIdentity
+// record.
+type BeaconResolver struct {
+	invoker ChaincodeInvoker
+}
+
+// NewBeaconResolver returns a resolver for the did:beacon method, querying
+// the chaincode reachable through invoker.
+func NewBeaconResolver(invoker ChaincodeInvoker) *BeaconResolver {
+	return &BeaconResolver{invoker: invoker}
+}
+
+// Resolve queries getDIDDocument(did) and parses its response.
+func (r *BeaconResolver) Resolve(did string) (*Document, error) {
+	method, err := Method(did)
+	if err != nil {
+		return nil, err
+	}
+	if method != "beacon" {
+		return nil, fmt.Errorf("not a did:beacon identifier: %s", did)
+	}
+
+	responseBytes, err := r.invoker.Invoke("getDIDDocument", []string{did})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query getDIDDocument: %w", err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(responseBytes, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse DID document: %w", err)
+	}
+	return &doc, nil
+}
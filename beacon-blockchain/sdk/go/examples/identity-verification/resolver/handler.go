@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// resolutionResult is the Universal Resolver-compatible response envelope
+// for a DID resolution request.
+type resolutionResult struct {
+	Context               string                 `json:"@context"`
+	DIDDocument           *Document              `json:"didDocument,omitempty"`
+	DIDResolutionMetadata map[string]interface{} `json:"didResolutionMetadata"`
+	DIDDocumentMetadata   map[string]interface{} `json:"didDocumentMetadata"`
+}
+
+// ResolverHandler serves DID resolution over HTTP at
+// /1.0/identifiers/{did}, per the Universal Resolver Driver interface.
+// Mount it as a peer-side endpoint backed by a Registry with whichever
+// methods (did:key, did:web, did:beacon, ...) the deployment supports.
+type ResolverHandler struct {
+	registry *Registry
+}
+
+// NewResolverHandler returns a ResolverHandler serving DIDs through registry.
+func NewResolverHandler(registry *Registry) *ResolverHandler {
+	return &ResolverHandler{registry: registry}
+}
+
+// ServeHTTP implements http.Handler, resolving the DID named by the
+// "/1.0/identifiers/" path prefix.
+func (h *ResolverHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	did := strings.TrimPrefix(r.URL.Path, "/1.0/identifiers/")
+	if did == "" || did == r.URL.Path {
+		http.Error(w, "missing DID in request path", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/ld+json")
+
+	doc, err := h.registry.Resolve(did)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(resolutionResult{
+			Context:               "https://w3id.org/did-resolution/v1",
+			DIDResolutionMetadata: map[string]interface{}{"error": "notFound", "message": err.Error()},
+			DIDDocumentMetadata:   map[string]interface{}{},
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(resolutionResult{
+		Context:               "https://w3id.org/did-resolution/v1",
+		DIDDocument:           doc,
+		DIDResolutionMetadata: map[string]interface{}{"contentType": "application/did+ld+json"},
+		DIDDocumentMetadata:   map[string]interface{}{},
+	})
+}
@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// base58Alphabet is the Bitcoin/IPFS base58btc alphabet used by did:key's
+// multibase "z" prefix.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// multicodecEd25519PublicKey is the multicodec varint prefix (0xed, 0x01)
+// identifying an Ed25519 public key within a did:key multibase value.
+var multicodecEd25519PublicKey = []byte{0xed, 0x01}
+
+// KeyResolver resolves did:key identifiers by decoding their
+// multibase-encoded public key directly; unlike did:web and did:beacon it
+// needs no network call or ledger lookup, since a did:key identifier is
+// self-certifying.
+type KeyResolver struct{}
+
+// NewKeyResolver returns a resolver for the did:key method.
+func NewKeyResolver() *KeyResolver {
+	return &KeyResolver{}
+}
+
+// Resolve decodes did's multibase value into a single-verification-method
+// DID Document. Only Ed25519 (multicodec 0xed01) keys are supported,
+// matching the rest of this package's Ed25519-only signature support.
+func (KeyResolver) Resolve(did string) (*Document, error) {
+	method, err := Method(did)
+	if err != nil {
+		return nil, err
+	}
+	if method != "key" {
+		return nil, fmt.Errorf("not a did:key identifier: %s", did)
+	}
+
+	multibaseValue := strings.SplitN(did, ":", 3)[2]
+	if _, err := decodeMultibaseEd25519(multibaseValue); err != nil {
+		return nil, fmt.Errorf("failed to decode did:key %s: %w", did, err)
+	}
+
+	verificationMethodID := did + "#" + multibaseValue
+	return &Document{
+		Context: []string{ContextDIDv1},
+		ID:      did,
+		VerificationMethod: []VerificationMethod{{
+			ID:                 verificationMethodID,
+			Type:               "Ed25519VerificationKey2020",
+			Controller:         did,
+			PublicKeyMultibase: multibaseValue,
+		}},
+		Authentication:  []string{verificationMethodID},
+		AssertionMethod: []string{verificationMethodID},
+	}, nil
+}
+
+// decodeMultibaseEd25519 decodes a did:key multibase value ("z" followed
+// by base58btc) into a raw 32-byte Ed25519 public key, stripping its
+// leading multicodec prefix.
+func decodeMultibaseEd25519(multibaseValue string) ([]byte, error) {
+	if !strings.HasPrefix(multibaseValue, "z") {
+		return nil, fmt.Errorf("unsupported multibase prefix (only base58btc \"z\" is supported)")
+	}
+
+	decoded, err := decodeBase58(multibaseValue[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid base58btc encoding: %w", err)
+	}
+
+	if len(decoded) != len(multicodecEd25519PublicKey)+32 {
+		return nil, fmt.Errorf("unexpected key length %d", len(decoded))
+	}
+	for i, b := range multicodecEd25519PublicKey {
+		if decoded[i] != b {
+			return nil, fmt.Errorf("unsupported multicodec prefix (only Ed25519 public keys are supported)")
+		}
+	}
+
+	return decoded[len(multicodecEd25519PublicKey):], nil
+}
+
+// EncodeMultibaseEd25519 encodes a base64-standard-encoded raw 32-byte
+// Ed25519 public key (the format this SDK stores elsewhere, e.g.
+// Identity.PublicKey) as a did:key-style multibase value, for
+// synthesizing DID Documents on the fly from legacy key material.
+func EncodeMultibaseEd25519(publicKeyBase64 string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(publicKeyBase64)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 public key: %w", err)
+	}
+	if len(raw) != 32 {
+		return "", fmt.Errorf("unexpected Ed25519 public key length %d", len(raw))
+	}
+
+	prefixed := make([]byte, 0, len(multicodecEd25519PublicKey)+len(raw))
+	prefixed = append(prefixed, multicodecEd25519PublicKey...)
+	prefixed = append(prefixed, raw...)
+	return "z" + encodeBase58(prefixed), nil
+}
+
+// encodeBase58 encodes data as base58btc, representing each leading zero
+// byte with the alphabet's zero digit ("1").
+func encodeBase58(data []byte) string {
+	zero := base58Alphabet[0]
+
+	leadingZeros := 0
+	for leadingZeros < len(data) && data[leadingZeros] == 0 {
+		leadingZeros++
+	}
+
+	value := new(big.Int).SetBytes(data)
+	base := big.NewInt(58)
+	mod := new(big.Int)
+
+	var encoded []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, base, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	out := make([]byte, leadingZeros, leadingZeros+len(encoded))
+	for i := range out {
+		out[i] = zero
+	}
+	return string(append(out, encoded...))
+}
+
+// decodeBase58 decodes a base58btc string, including its leading-zero
+// ("1") run as leading zero bytes.
+func decodeBase58(s string) ([]byte, error) {
+	zero := byte(base58Alphabet[0])
+
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == zero {
+		leadingZeros++
+	}
+
+	result := big.NewInt(0)
+	base := big.NewInt(58)
+	for _, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		result.Mul(result, base)
+		result.Add(result, big.NewInt(int64(digit)))
+	}
+
+	decoded := result.Bytes()
+	out := make([]byte, leadingZeros+len(decoded))
+	copy(out[leadingZeros:], decoded)
+	return out, nil
+}
@@ -0,0 +1,106 @@
+// Package resolver implements DID resolution for the identity-verification
+// chaincode: a pluggable DIDResolver interface, built-in resolvers for the
+// did:key, did:web, and did:beacon methods, and a Universal
+// Resolver-compatible HTTP endpoint for driving that resolution from
+// outside a transaction.
+package resolver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ContextDIDv1 is the JSON-LD context of a DID Document.
+const ContextDIDv1 = "https://www.w3.org/ns/did/v1"
+
+// VerificationMethod is one key a DID Document authorizes for some
+// purpose (authentication, assertion, ...), identified relative to its
+// DID Document by ID.
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"`
+}
+
+// PublicKeyBase64 decodes this verification method's key material into the
+// base64-standard-encoded raw public key this SDK's shim package expects,
+// e.g. as Identity.PublicKey or a vc.Proof's signing key.
+func (vm VerificationMethod) PublicKeyBase64() (string, error) {
+	if vm.PublicKeyMultibase == "" {
+		return "", fmt.Errorf("verification method %q has no publicKeyMultibase", vm.ID)
+	}
+	raw, err := decodeMultibaseEd25519(vm.PublicKeyMultibase)
+	if err != nil {
+		return "", fmt.Errorf("verification method %q: %w", vm.ID, err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// Document is a W3C DID Document, scoped to the fields this chaincode
+// needs to resolve an issuer's signing key.
+type Document struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Authentication     []string             `json:"authentication,omitempty"`
+	AssertionMethod    []string             `json:"assertionMethod,omitempty"`
+}
+
+// VerificationMethodByID returns the verification method in d whose id
+// matches verificationMethodID, as referenced by a credential's
+// proof.verificationMethod.
+func (d *Document) VerificationMethodByID(verificationMethodID string) (*VerificationMethod, error) {
+	for i := range d.VerificationMethod {
+		if d.VerificationMethod[i].ID == verificationMethodID {
+			return &d.VerificationMethod[i], nil
+		}
+	}
+	return nil, fmt.Errorf("verification method %q not found in DID document %q", verificationMethodID, d.ID)
+}
+
+// Method returns the DID method of did, e.g. Method("did:key:z6Mk...")
+// returns "key".
+func Method(did string) (string, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) < 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("malformed DID: %s", did)
+	}
+	return parts[1], nil
+}
+
+// DIDResolver resolves a DID to its DID Document.
+type DIDResolver interface {
+	Resolve(did string) (*Document, error)
+}
+
+// Registry dispatches Resolve calls to the DIDResolver registered for a
+// DID's method.
+type Registry struct {
+	resolvers map[string]DIDResolver
+}
+
+// NewRegistry returns an empty Registry; use Register to add methods.
+func NewRegistry() *Registry {
+	return &Registry{resolvers: make(map[string]DIDResolver)}
+}
+
+// Register associates a DID method (e.g. "key", "web", "beacon") with the
+// resolver that handles it.
+func (r *Registry) Register(method string, resolver DIDResolver) {
+	r.resolvers[method] = resolver
+}
+
+// Resolve dispatches did to the resolver registered for its method.
+func (r *Registry) Resolve(did string) (*Document, error) {
+	method, err := Method(did)
+	if err != nil {
+		return nil, err
+	}
+	resolver, ok := r.resolvers[method]
+	if !ok {
+		return nil, fmt.Errorf("no resolver registered for DID method %q", method)
+	}
+	return resolver.Resolve(did)
+}
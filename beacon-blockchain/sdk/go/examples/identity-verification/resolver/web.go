@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebResolver resolves did:web identifiers by fetching their DID Document
+// over HTTPS. It performs a real network call and so is only usable
+// off-chain (e.g. from ResolverHandler); chaincode execution must stay
+// deterministic and cannot call it directly — a did:web issuer's document
+// should be resolved here and cached on-chain via registerDIDDocument
+// before it is used to verify a credential.
+type WebResolver struct {
+	client *http.Client
+}
+
+// NewWebResolver returns a WebResolver using client, or a 10-second-timeout
+// default client if client is nil.
+func NewWebResolver(client *http.Client) *WebResolver {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebResolver{client: client}
+}
+
+// Resolve fetches did's DID Document per the did:web method spec: the
+// method-specific identifier is a domain (and optional colon-separated
+// path), mapped to an HTTPS URL under /.well-known/ (or that path) ending
+// in did.json.
+func (r *WebResolver) Resolve(did string) (*Document, error) {
+	method, err := Method(did)
+	if err != nil {
+		return nil, err
+	}
+	if method != "web" {
+		return nil, fmt.Errorf("not a did:web identifier: %s", did)
+	}
+
+	parts := strings.Split(did, ":")
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("malformed did:web identifier: %s", did)
+	}
+
+	domain, err := url.QueryUnescape(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid did:web domain %q: %w", parts[2], err)
+	}
+
+	var docURL string
+	if len(parts) == 3 {
+		docURL = fmt.Sprintf("https://%s/.well-known/did.json", domain)
+	} else {
+		segments := make([]string, len(parts)-3)
+		for i, seg := range parts[3:] {
+			unescaped, err := url.QueryUnescape(seg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid did:web path segment %q: %w", seg, err)
+			}
+			segments[i] = unescaped
+		}
+		docURL = fmt.Sprintf("https://%s/%s/did.json", domain, strings.Join(segments, "/"))
+	}
+
+	resp, err := r.client.Get(docURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch DID document from %s: %w", docURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch DID document from %s: HTTP %d", docURL, resp.StatusCode)
+	}
+
+	var doc Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse DID document from %s: %w", docURL, err)
+	}
+	if doc.ID != did {
+		return nil, fmt.Errorf("DID document id %q does not match requested DID %q", doc.ID, did)
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,218 @@
+// Package schema implements a JSON Schema subset validator and a JSON-LD
+// @context term-membership check, used by the chaincode to enforce that a
+// credential's claims conform to the credentialSchema its issuer
+// referenced before they are stored.
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Record is one registered, versioned credential schema: a JSON Schema
+// validating claim structure, and a JSON-LD context defining which claim
+// keys are recognized terms. Updating a schema creates a new Record
+// rather than mutating an existing one, so credentials keep pointing at
+// the exact version they were validated against.
+type Record struct {
+	ID            string          `json:"id"`
+	Version       int             `json:"version"`
+	JSONSchema    json.RawMessage `json:"jsonSchema"`
+	JSONLDContext json.RawMessage `json:"jsonldContext"`
+	ContextHash   string          `json:"contextHash"`
+	CreatedAt     int64           `json:"createdAt"`
+}
+
+// HashContext returns the base64-standard-encoded SHA-256 digest of a
+// JSON-LD context document, recorded on a Record so a later silent edit
+// to the same context is detectable.
+func HashContext(jsonldContext []byte) string {
+	digest := sha256.Sum256(jsonldContext)
+	return base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// ValidateClaims validates claims against record's JSON Schema (the
+// "type", "required", "properties", "enum", "pattern", "minimum", and
+// "maximum" keywords) and confirms every claim key other than "id" (the
+// VC Data Model's own subject identifier, not an issuer-defined claim) is
+// a term defined in record's JSON-LD context. It returns the first
+// violation found.
+func ValidateClaims(claims map[string]interface{}, record *Record) error {
+	var jsonSchema map[string]interface{}
+	if err := json.Unmarshal(record.JSONSchema, &jsonSchema); err != nil {
+		return fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	if err := validateObject(claims, jsonSchema); err != nil {
+		return err
+	}
+
+	terms, err := contextTerms(record.JSONLDContext)
+	if err != nil {
+		return err
+	}
+	for key := range claims {
+		if key == "id" {
+			continue
+		}
+		if !terms[key] {
+			return fmt.Errorf("claim %q is not a term defined in credentialSchema %q's JSON-LD context", key, record.ID)
+		}
+	}
+
+	return nil
+}
+
+// contextTerms collects the term names a JSON-LD context document defines
+// under its top-level "@context" object, ignoring keyword entries
+// ("@version", "@vocab", "@base", ...).
+func contextTerms(jsonldContext []byte) (map[string]bool, error) {
+	var doc struct {
+		Context map[string]interface{} `json:"@context"`
+	}
+	if err := json.Unmarshal(jsonldContext, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON-LD context: %w", err)
+	}
+
+	terms := make(map[string]bool, len(doc.Context))
+	for term := range doc.Context {
+		if term == "" || term[0] == '@' {
+			continue
+		}
+		terms[term] = true
+	}
+	return terms, nil
+}
+
+// validateObject validates value against a JSON Schema object schema:
+// every "required" field is present, and each property value present in
+// value satisfies its "properties" entry via validateValue.
+func validateObject(value map[string]interface{}, jsonSchema map[string]interface{}) error {
+	if required, ok := jsonSchema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+			if _, present := value[name]; !present {
+				return fmt.Errorf("missing required claim %q", name)
+			}
+		}
+	}
+
+	properties, _ := jsonSchema["properties"].(map[string]interface{})
+	for name, propSchemaRaw := range properties {
+		propSchema, ok := propSchemaRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldValue, present := value[name]
+		if !present {
+			continue
+		}
+		if err := validateValue(name, fieldValue, propSchema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue validates one claim's value against its property schema's
+// "type", "enum", "pattern" (strings), and "minimum"/"maximum" (numbers)
+// keywords.
+func validateValue(name string, value interface{}, propSchema map[string]interface{}) error {
+	if expectedType, ok := propSchema["type"].(string); ok && !matchesType(value, expectedType) {
+		return fmt.Errorf("claim %q has type %s, expected %s", name, jsonTypeName(value), expectedType)
+	}
+
+	if enum, ok := propSchema["enum"].([]interface{}); ok {
+		matched := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("claim %q value %v is not one of its schema's allowed enum values", name, value)
+		}
+	}
+
+	if pattern, ok := propSchema["pattern"].(string); ok {
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("claim %q must be a string to match pattern %q", name, pattern)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("claim %q schema has an invalid pattern %q: %w", name, pattern, err)
+		}
+		if !re.MatchString(str) {
+			return fmt.Errorf("claim %q value %q does not match pattern %q", name, str, pattern)
+		}
+	}
+
+	if num, isNum := value.(float64); isNum {
+		if min, ok := propSchema["minimum"].(float64); ok && num < min {
+			return fmt.Errorf("claim %q value %v is below minimum %v", name, num, min)
+		}
+		if max, ok := propSchema["maximum"].(float64); ok && num > max {
+			return fmt.Errorf("claim %q value %v is above maximum %v", name, num, max)
+		}
+	}
+
+	return nil
+}
+
+// matchesType reports whether value's decoded JSON type matches
+// expectedType, per JSON Schema's "type" keyword vocabulary. An
+// unrecognized expectedType is treated as unconstrained.
+func matchesType(value interface{}, expectedType string) bool {
+	switch expectedType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		num, ok := value.(float64)
+		return ok && num == float64(int64(num))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+// jsonTypeName names value's decoded JSON type for error messages.
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,127 @@
+package vc
+
+import (
+	"fmt"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// ReasonCode is a CRL entry revocation reason, using the same numeric
+// values RFC 5280 §5.3.1 assigns CRLReason so this chaincode's revocation
+// lists interoperate with standard X.509 CRL tooling.
+type ReasonCode int
+
+// The subset of RFC 5280 CRLReason values this chaincode accepts. Value 7
+// is reserved by the RFC and deliberately has no name here.
+const (
+	ReasonUnspecified          ReasonCode = 0
+	ReasonKeyCompromise        ReasonCode = 1
+	ReasonCACompromise         ReasonCode = 2
+	ReasonAffiliationChanged   ReasonCode = 3
+	ReasonSuperseded           ReasonCode = 4
+	ReasonCessationOfOperation ReasonCode = 5
+	ReasonCertificateHold      ReasonCode = 6
+	ReasonRemoveFromCRL        ReasonCode = 8
+	ReasonPrivilegeWithdrawn   ReasonCode = 9
+)
+
+// reasonCodesByName maps the wire names callers pass (and that
+// RevocationEntry.ReasonName renders back) to their RFC 5280 numeric code.
+var reasonCodesByName = map[string]ReasonCode{
+	"unspecified":          ReasonUnspecified,
+	"keyCompromise":        ReasonKeyCompromise,
+	"cACompromise":         ReasonCACompromise,
+	"affiliationChanged":   ReasonAffiliationChanged,
+	"superseded":           ReasonSuperseded,
+	"cessationOfOperation": ReasonCessationOfOperation,
+	"certificateHold":      ReasonCertificateHold,
+	"removeFromCRL":        ReasonRemoveFromCRL,
+	"privilegeWithdrawn":   ReasonPrivilegeWithdrawn,
+}
+
+// ParseReasonCode resolves a reason name (e.g. "keyCompromise") to its
+// RFC 5280 numeric code, rejecting anything outside the standardized set.
+func ParseReasonCode(name string) (ReasonCode, error) {
+	code, ok := reasonCodesByName[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported revocation reason code: %s", name)
+	}
+	return code, nil
+}
+
+// ReasonName renders a ReasonCode back to its wire name, or "unspecified"
+// if it wasn't one ParseReasonCode would have accepted.
+func (r ReasonCode) ReasonName() string {
+	for name, code := range reasonCodesByName {
+		if code == r {
+			return name
+		}
+	}
+	return "unspecified"
+}
+
+// RevocationEntry is one credential's entry in a RevocationListSnapshot.
+// CRLNumber records the issuer's CRL numbering sequence value the
+// credential was revoked under, so generateDeltaRevocationList can select
+// just the entries newer than a given snapshot.
+type RevocationEntry struct {
+	CredentialID string     `json:"credentialID"`
+	ReasonCode   ReasonCode `json:"reasonCode"`
+	RevokedAt    int64      `json:"revokedAt"`
+	CRLNumber    int        `json:"crlNumber"`
+}
+
+// RevocationListSnapshot is a signed, versioned snapshot of an issuer's
+// revoked credentials, analogous to an X.509 CRL: CRLNumber increases
+// monotonically across snapshots, and ThisUpdate/NextUpdate bound the
+// period during which it's considered current.
+type RevocationListSnapshot struct {
+	Issuer     string            `json:"issuer"`
+	CRLNumber  int               `json:"crlNumber"`
+	ThisUpdate string            `json:"thisUpdate"`
+	NextUpdate string            `json:"nextUpdate,omitempty"`
+	IsDelta    bool              `json:"isDelta,omitempty"`
+	Entries    []RevocationEntry `json:"revokedCredentials"`
+	Proof      *Proof            `json:"proof,omitempty"`
+}
+
+// CanonicalizeRevocationList returns the shim.CanonicalJSON serialization
+// of a snapshot with its proof stripped, mirroring Canonicalize: this is what
+// the issuer signs off-chain and what the chaincode recomputes to verify
+// that signature.
+func CanonicalizeRevocationList(snapshot RevocationListSnapshot) ([]byte, error) {
+	snapshot.Proof = nil
+
+	canonical, err := shim.CanonicalJSON(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize revocation list: %w", err)
+	}
+	return canonical, nil
+}
+
+// VerifyRevocationListProof reports whether snapshot.Proof is a valid
+// Ed25519Signature2020 signature over the snapshot's canonical form under
+// issuerPublicKey, produced by expectedVerificationMethod.
+func VerifyRevocationListProof(snapshot *RevocationListSnapshot, issuerPublicKey, expectedVerificationMethod string) (bool, error) {
+	if snapshot.Proof == nil {
+		return false, fmt.Errorf("revocation list has no proof")
+	}
+	if snapshot.Proof.Type != ProofTypeEd25519Signature2020 {
+		return false, fmt.Errorf("unsupported proof type: %s", snapshot.Proof.Type)
+	}
+	if snapshot.Proof.VerificationMethod != expectedVerificationMethod {
+		return false, fmt.Errorf("proof verificationMethod %q does not match issuer's registered key %q", snapshot.Proof.VerificationMethod, expectedVerificationMethod)
+	}
+
+	signature, err := shim.DecodeSignature(snapshot.Proof.ProofValue)
+	if err != nil {
+		return false, err
+	}
+
+	canonical, err := CanonicalizeRevocationList(*snapshot)
+	if err != nil {
+		return false, err
+	}
+
+	return shim.VerifySignature(issuerPublicKey, shim.AlgorithmEd25519, canonical, signature)
+}
@@ -0,0 +1,120 @@
+package vc
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// JWTClaims holds the standard registered claims carried by a JWT-secured
+// Verifiable Credential ("JWT-VC"), with the VC payload itself nested
+// under the vc claim per the JWT encoding of the VC Data Model.
+type JWTClaims struct {
+	Issuer    string                `json:"iss"`
+	Subject   string                `json:"sub"`
+	NotBefore int64                 `json:"nbf,omitempty"`
+	ExpiresAt int64                 `json:"exp,omitempty"`
+	JWTID     string                `json:"jti,omitempty"`
+	VC        *VerifiableCredential `json:"vc"`
+	CNF       *ConfirmationKey      `json:"cnf,omitempty"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// AlgorithmForProofType maps a Data Integrity proof type to the JOSE "alg"
+// header value a JWT-VC carrying an equivalent signature would declare.
+func AlgorithmForProofType(proofType string) (string, error) {
+	switch proofType {
+	case ProofTypeEd25519Signature2020:
+		return "EdDSA", nil
+	case "EcdsaSecp256k1Signature2019":
+		return "ES256K", nil
+	case "RsaSignature2018":
+		return "RS256", nil
+	default:
+		return "", fmt.Errorf("unknown proof type: %s", proofType)
+	}
+}
+
+// EncodeJWTVC signs claims as a compact JWS and returns the resulting
+// JWT-VC. Only EdDSA (Ed25519) is currently supported for signing; ES256K
+// and RS256 are recognized by AlgorithmForProofType but not yet backed by
+// a verifier in the shim.
+func EncodeJWTVC(claims JWTClaims, signer ed25519.PrivateKey, alg string) (string, error) {
+	if alg != "EdDSA" {
+		return "", fmt.Errorf("unsupported JWT-VC signing algorithm: %s", alg)
+	}
+
+	headerBytes, err := json.Marshal(jwtHeader{Alg: alg, Typ: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT header: %w", err)
+	}
+	payloadBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(payloadBytes)
+	signature := ed25519.Sign(signer, []byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyJWTVC parses and verifies a compact JWS carrying a VC in its vc
+// claim, checking that its header alg matches expectedAlg (derived from
+// the issuer's registered key type) and that its signature is valid under
+// issuerPublicKey. Only EdDSA (Ed25519) is currently supported for
+// verification.
+func VerifyJWTVC(token, issuerPublicKey, expectedAlg string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT header: %w", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse JWT header: %w", err)
+	}
+	if header.Alg != expectedAlg {
+		return nil, fmt.Errorf("JWT alg %q does not match issuer's registered key type (expected %q)", header.Alg, expectedAlg)
+	}
+	if header.Alg != "EdDSA" {
+		return nil, fmt.Errorf("unsupported JWT-VC verification algorithm: %s", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT signature: %w", err)
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+	valid, err := shim.VerifySignature(issuerPublicKey, shim.AlgorithmEd25519, signingInput, signature)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("JWT signature verification failed")
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT payload: %w", err)
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse JWT claims: %w", err)
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,300 @@
+package vc
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SDAlgSHA256 is the only "_sd_alg" this package produces or accepts.
+const SDAlgSHA256 = "sha-256"
+
+// keyBindingJWTTyp is the "typ" header a holder's key-binding JWT must
+// declare, per the SD-JWT VC key-binding JWT format.
+const keyBindingJWTTyp = "kb+jwt"
+
+// ConfirmationKey is the "cnf" claim of an SD-JWT VC, binding the
+// credential to the holder's public key per RFC 7800.
+type ConfirmationKey struct {
+	JWK JWK `json:"jwk"`
+}
+
+// JWK is a minimal JSON Web Key, carrying only what this package needs to
+// bind and verify an Ed25519 holder key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// KeyBindingClaims holds the claims of a holder's key-binding JWT: proof
+// that the holder controls the key a credential's cnf claim was bound to,
+// scoped to one verifier and one presentation.
+type KeyBindingClaims struct {
+	Audience string `json:"aud"`
+	Nonce    string `json:"nonce"`
+	IssuedAt int64  `json:"iat"`
+	SDHash   string `json:"sd_hash"`
+}
+
+// disclosureSalt deterministically derives a disclosure's salt from the
+// credential ID and claim name. A real SD-JWT issuer would instead draw a
+// fresh random salt per disclosure for full per-presentation
+// unlinkability; this chaincode has no side channel to hand such a salt
+// back to a verifying peer ahead of time, so it derives one instead,
+// letting both the issuer and the chaincode reconstruct byte-identical
+// disclosures without a prior round trip.
+func disclosureSalt(credentialID, claimName string) string {
+	mac := hmac.New(sha256.New, []byte(credentialID))
+	mac.Write([]byte(claimName))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// BuildDisclosures computes a compact disclosure for each claim named in
+// disclosableNames, salted via disclosureSalt, and returns claims with
+// those entries removed and replaced by their digests under "_sd" (plus
+// "_sd_alg"). disclosures is ordered to match disclosableNames.
+func BuildDisclosures(credentialID string, claims map[string]interface{}, disclosableNames []string) (redacted map[string]interface{}, disclosures []string, err error) {
+	redacted = make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		redacted[k] = v
+	}
+	if len(disclosableNames) == 0 {
+		return redacted, nil, nil
+	}
+
+	sd := make([]string, 0, len(disclosableNames))
+	for _, name := range disclosableNames {
+		value, ok := claims[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("disclosable claim %q not present in claims", name)
+		}
+
+		disclosureJSON, err := json.Marshal([]interface{}{disclosureSalt(credentialID, name), name, value})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode disclosure for %q: %w", name, err)
+		}
+		disclosure := base64.RawURLEncoding.EncodeToString(disclosureJSON)
+
+		disclosures = append(disclosures, disclosure)
+		sd = append(sd, DigestDisclosure(disclosure))
+		delete(redacted, name)
+	}
+
+	redacted["_sd"] = sd
+	redacted["_sd_alg"] = SDAlgSHA256
+	return redacted, disclosures, nil
+}
+
+// DigestDisclosure returns the base64url SHA-256 digest of a compact
+// disclosure, as embedded in an "_sd" array.
+func DigestDisclosure(disclosure string) string {
+	digest := sha256.Sum256([]byte(disclosure))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// VerifyDisclosures recomputes each disclosure's digest, confirms it
+// appears in credentialSubject's "_sd" array, and returns the {name:
+// value} claims it reveals.
+func VerifyDisclosures(credentialSubject map[string]interface{}, disclosures []string) (map[string]interface{}, error) {
+	disclosed := make(map[string]interface{}, len(disclosures))
+	if len(disclosures) == 0 {
+		return disclosed, nil
+	}
+
+	sdRaw, ok := credentialSubject["_sd"]
+	if !ok {
+		return nil, fmt.Errorf("credential has no _sd array to disclose against")
+	}
+	sdList, ok := sdRaw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("credential's _sd array is malformed")
+	}
+	digests := make(map[string]bool, len(sdList))
+	for _, d := range sdList {
+		if s, ok := d.(string); ok {
+			digests[s] = true
+		}
+	}
+
+	for _, disclosure := range disclosures {
+		if !digests[DigestDisclosure(disclosure)] {
+			return nil, fmt.Errorf("disclosure does not match any digest in _sd")
+		}
+
+		raw, err := base64.RawURLEncoding.DecodeString(disclosure)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode disclosure: %w", err)
+		}
+		var parts []json.RawMessage
+		if err := json.Unmarshal(raw, &parts); err != nil || len(parts) != 3 {
+			return nil, fmt.Errorf("malformed disclosure: expected [salt, name, value]")
+		}
+		var name string
+		if err := json.Unmarshal(parts[1], &name); err != nil {
+			return nil, fmt.Errorf("malformed disclosure claim name: %w", err)
+		}
+		var value interface{}
+		if err := json.Unmarshal(parts[2], &value); err != nil {
+			return nil, fmt.Errorf("malformed disclosure claim value: %w", err)
+		}
+		disclosed[name] = value
+	}
+
+	return disclosed, nil
+}
+
+// VisibleSubject returns credentialSubject with its selective-disclosure
+// bookkeeping fields removed, i.e. the claims that are always revealed
+// regardless of which disclosures a holder presents.
+func VisibleSubject(credentialSubject map[string]interface{}) map[string]interface{} {
+	visible := make(map[string]interface{}, len(credentialSubject))
+	for k, v := range credentialSubject {
+		if k == "_sd" || k == "_sd_alg" {
+			continue
+		}
+		visible[k] = v
+	}
+	return visible
+}
+
+// EncodeSDJWTVC redacts disclosableNames out of claims.VC.CredentialSubject
+// into an "_sd" array (see BuildDisclosures), signs the resulting VC as a
+// JWT-VC, and returns the compact SD-JWT presentation (the signed JWT
+// followed by "~"-joined disclosures) alongside the disclosures
+// themselves.
+func EncodeSDJWTVC(credentialID string, claims JWTClaims, disclosableNames []string, signer ed25519.PrivateKey, alg string) (sdJWT string, disclosures []string, err error) {
+	if claims.VC == nil {
+		return "", nil, fmt.Errorf("claims.VC must be set")
+	}
+
+	redacted, disclosures, err := BuildDisclosures(credentialID, claims.VC.CredentialSubject, disclosableNames)
+	if err != nil {
+		return "", nil, err
+	}
+	claims.VC.CredentialSubject = redacted
+
+	jwt, err := EncodeJWTVC(claims, signer, alg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return EncodeSDJWT(jwt, disclosures), disclosures, nil
+}
+
+// EncodeSDJWT joins a signed JWT-VC with its disclosures into the compact
+// SD-JWT presentation format: "<jwt>~<disclosure>~...~", leaving a
+// trailing "~" for an optional key-binding JWT to be appended to.
+func EncodeSDJWT(jwt string, disclosures []string) string {
+	var b strings.Builder
+	b.WriteString(jwt)
+	for _, d := range disclosures {
+		b.WriteString("~")
+		b.WriteString(d)
+	}
+	b.WriteString("~")
+	return b.String()
+}
+
+// SplitSDJWT parses the compact SD-JWT presentation format into its
+// signed JWT, disclosures, and optional key-binding JWT (empty if none
+// was appended).
+func SplitSDJWT(sdJWT string) (jwt string, disclosures []string, keyBindingJWT string, err error) {
+	parts := strings.Split(sdJWT, "~")
+	if len(parts) < 2 {
+		return "", nil, "", fmt.Errorf("malformed SD-JWT: expected at least one '~'")
+	}
+
+	jwt = parts[0]
+	body := parts[1:]
+	if last := body[len(body)-1]; last != "" {
+		keyBindingJWT = last
+		body = body[:len(body)-1]
+	}
+	for _, d := range body {
+		if d != "" {
+			disclosures = append(disclosures, d)
+		}
+	}
+	return jwt, disclosures, keyBindingJWT, nil
+}
+
+// VerifyKeyBindingJWT checks that keyBindingJWT is an EdDSA-signed
+// "kb+jwt" under the key in cnf, bound via "sd_hash" to presentedSDJWT
+// (the SD-JWT it accompanies, without the key-binding JWT itself), and
+// carries the expected audience and nonce.
+func VerifyKeyBindingJWT(keyBindingJWT string, cnf *ConfirmationKey, presentedSDJWT, expectedAudience, expectedNonce string) error {
+	if cnf == nil {
+		return fmt.Errorf("credential has no cnf claim to bind a key-binding JWT against")
+	}
+	if cnf.JWK.Kty != "OKP" || cnf.JWK.Crv != "Ed25519" {
+		return fmt.Errorf("unsupported cnf key type: %s/%s", cnf.JWK.Kty, cnf.JWK.Crv)
+	}
+	holderKey, err := base64.RawURLEncoding.DecodeString(cnf.JWK.X)
+	if err != nil {
+		return fmt.Errorf("failed to decode holder public key: %w", err)
+	}
+	if len(holderKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid holder public key length: %d", len(holderKey))
+	}
+
+	parts := strings.Split(keyBindingJWT, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed key-binding JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("failed to decode key-binding JWT header: %w", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return fmt.Errorf("failed to parse key-binding JWT header: %w", err)
+	}
+	if header.Typ != keyBindingJWTTyp {
+		return fmt.Errorf("key-binding JWT typ %q does not match expected %q", header.Typ, keyBindingJWTTyp)
+	}
+	if header.Alg != "EdDSA" {
+		return fmt.Errorf("unsupported key-binding JWT algorithm: %s", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("failed to decode key-binding JWT signature: %w", err)
+	}
+	signingInput := []byte(parts[0] + "." + parts[1])
+	if !ed25519.Verify(holderKey, signingInput, signature) {
+		return fmt.Errorf("key-binding JWT signature verification failed")
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("failed to decode key-binding JWT payload: %w", err)
+	}
+	var claims KeyBindingClaims
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil {
+		return fmt.Errorf("failed to parse key-binding JWT payload: %w", err)
+	}
+	if claims.Audience != expectedAudience {
+		return fmt.Errorf("key-binding JWT aud %q does not match expected %q", claims.Audience, expectedAudience)
+	}
+	if claims.Nonce != expectedNonce {
+		return fmt.Errorf("key-binding JWT nonce does not match expected value")
+	}
+	if claims.SDHash != sdHash(presentedSDJWT) {
+		return fmt.Errorf("key-binding JWT sd_hash does not match the presented SD-JWT")
+	}
+
+	return nil
+}
+
+// sdHash returns the base64url SHA-256 digest of a presented SD-JWT,
+// binding a key-binding JWT to one specific presentation.
+func sdHash(presentedSDJWT string) string {
+	digest := sha256.Sum256([]byte(presentedSDJWT))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
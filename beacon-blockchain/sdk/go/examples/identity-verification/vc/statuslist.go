@@ -0,0 +1,147 @@
+package vc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+)
+
+// StatusList2021Capacity is the number of entries (bits) in a single
+// status list, per the Status List 2021 specification. Once a list's
+// entries are exhausted, issuance moves on to a new list.
+const StatusList2021Capacity = 131072
+
+// Well-known type and status-purpose values for Status List 2021.
+const (
+	TypeStatusList2021Credential = "StatusList2021Credential"
+	TypeStatusList2021           = "StatusList2021"
+	TypeStatusList2021Entry      = "StatusList2021Entry"
+	StatusPurposeRevocation      = "revocation"
+	StatusPurposeSuspension      = "suspension"
+)
+
+// StatusList2021Subject is the credentialSubject of a
+// StatusList2021Credential: a gzip'd, base64url-encoded bitstring where
+// each bit records one credential's revocation state.
+type StatusList2021Subject struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	StatusPurpose string `json:"statusPurpose"`
+	EncodedList   string `json:"encodedList"`
+}
+
+// NewStatusListBitstring returns a zeroed bitstring of StatusList2021Capacity
+// bits — every credential assigned into it starts out unrevoked.
+func NewStatusListBitstring() []byte {
+	return make([]byte, StatusList2021Capacity/8)
+}
+
+// SetBit sets (revoked=true) or clears (revoked=false) the bit at index in
+// bitstring.
+func SetBit(bitstring []byte, index int, revoked bool) error {
+	byteIndex, bitOffset, err := bitPosition(bitstring, index)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		bitstring[byteIndex] |= 1 << bitOffset
+	} else {
+		bitstring[byteIndex] &^= 1 << bitOffset
+	}
+	return nil
+}
+
+// IsRevoked reports whether the bit at index is set in bitstring.
+func IsRevoked(bitstring []byte, index int) (bool, error) {
+	byteIndex, bitOffset, err := bitPosition(bitstring, index)
+	if err != nil {
+		return false, err
+	}
+	return bitstring[byteIndex]&(1<<bitOffset) != 0, nil
+}
+
+func bitPosition(bitstring []byte, index int) (byteIndex int, bitOffset uint, err error) {
+	if index < 0 || index >= len(bitstring)*8 {
+		return 0, 0, fmt.Errorf("status list index %d out of range for a %d-bit list", index, len(bitstring)*8)
+	}
+	return index / 8, uint(index % 8), nil
+}
+
+// CompressBitstring gzip-compresses a bitstring for on-ledger storage,
+// where it's kept as raw bytes (no base64 wrapping, unlike EncodeBitstring)
+// since state values aren't JSON-embedded.
+func CompressBitstring(bitstring []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(bitstring); err != nil {
+		return nil, fmt.Errorf("failed to compress status list: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress status list: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressBitstring reverses CompressBitstring.
+func DecompressBitstring(compressed []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress status list: %w", err)
+	}
+	defer gz.Close()
+	bitstring, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress status list: %w", err)
+	}
+	return bitstring, nil
+}
+
+// EncodeBitstring gzip-compresses and base64url-encodes a bitstring for
+// embedding as a StatusList2021Credential's credentialSubject.encodedList.
+func EncodeBitstring(bitstring []byte) (string, error) {
+	compressed, err := CompressBitstring(bitstring)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(compressed), nil
+}
+
+// DecodeBitstring reverses EncodeBitstring.
+func DecodeBitstring(encoded string) ([]byte, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode status list: %w", err)
+	}
+	return DecompressBitstring(compressed)
+}
+
+// NewStatusListCredential builds a StatusList2021Credential carrying
+// bitstring as its encodedList for the given statusPurpose (revocation or
+// suspension). It is returned without a proof: the chaincode holds no
+// issuer private key, so verifiers that require a signed status list
+// should have the issuer re-sign this document off-chain on whatever
+// cadence it updates.
+func NewStatusListCredential(id, issuer string, bitstring []byte, issuedAt time.Time, statusPurpose string) (*VerifiableCredential, error) {
+	encodedList, err := EncodeBitstring(bitstring)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := NewBuilder().
+		AddType(TypeStatusList2021Credential).
+		SetID(id).
+		SetIssuer(issuer).
+		SetIssuanceDate(issuedAt).
+		SetCredentialSubject(map[string]interface{}{
+			"id":            id + "#list",
+			"type":          TypeStatusList2021,
+			"statusPurpose": statusPurpose,
+			"encodedList":   encodedList,
+		}).
+		Build()
+
+	return credential, nil
+}
@@ -0,0 +1,203 @@
+// Package vc implements a minimal W3C Verifiable Credentials Data Model:
+// a builder for constructing spec-conformant credentials, sorted-key
+// canonical JSON serialization of their contents (see Canonicalize), and
+// Ed25519Signature2020 proof verification.
+package vc
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// Well-known context, type, and proof type values used by credentials
+// issued by this chaincode.
+const (
+	ContextCredentialsV1           = "https://www.w3.org/2018/credentials/v1"
+	TypeVerifiableCredential       = "VerifiableCredential"
+	ProofTypeEd25519Signature2020  = "Ed25519Signature2020"
+	ProofPurposeAssertionMethod    = "assertionMethod"
+)
+
+// CredentialStatus points at the revocation mechanism backing a
+// credential: an entry in a StatusList2021 bitstring, identified by the
+// credential it points at plus the list and bit offset the subject was
+// assigned at issuance. See statuslist.go.
+type CredentialStatus struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose,omitempty"`
+	StatusListIndex      string `json:"statusListIndex,omitempty"`
+	StatusListCredential string `json:"statusListCredential,omitempty"`
+}
+
+// Proof is a W3C Data Integrity proof attached to a credential.
+type Proof struct {
+	Type               string `json:"type"`
+	Created            string `json:"created"`
+	VerificationMethod string `json:"verificationMethod"`
+	ProofPurpose       string `json:"proofPurpose"`
+	ProofValue         string `json:"proofValue"`
+}
+
+// VerifiableCredential is a W3C VC Data Model credential.
+type VerifiableCredential struct {
+	Context           []string               `json:"@context"`
+	ID                string                 `json:"id"`
+	Type              []string               `json:"type"`
+	Issuer            string                 `json:"issuer"`
+	IssuanceDate      string                 `json:"issuanceDate"`
+	ExpirationDate    string                 `json:"expirationDate,omitempty"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	CredentialStatus  *CredentialStatus      `json:"credentialStatus,omitempty"`
+	Proof             *Proof                 `json:"proof,omitempty"`
+}
+
+// Builder constructs a VerifiableCredential field by field, mirroring the
+// ssi-sdk VerifiableCredentialBuilder pattern so off-chain issuers can
+// assemble exactly the payload this chaincode will accept.
+type Builder struct {
+	vc VerifiableCredential
+}
+
+// NewBuilder starts a credential pre-populated with the base VC context
+// and the VerifiableCredential type.
+func NewBuilder() *Builder {
+	return &Builder{
+		vc: VerifiableCredential{
+			Context: []string{ContextCredentialsV1},
+			Type:    []string{TypeVerifiableCredential},
+		},
+	}
+}
+
+// AddContext appends an additional JSON-LD context.
+func (b *Builder) AddContext(context string) *Builder {
+	b.vc.Context = append(b.vc.Context, context)
+	return b
+}
+
+// AddType appends an additional credential type.
+func (b *Builder) AddType(credentialType string) *Builder {
+	b.vc.Type = append(b.vc.Type, credentialType)
+	return b
+}
+
+// SetID sets the credential's identifier.
+func (b *Builder) SetID(id string) *Builder {
+	b.vc.ID = id
+	return b
+}
+
+// SetIssuer sets the credential's issuer.
+func (b *Builder) SetIssuer(issuer string) *Builder {
+	b.vc.Issuer = issuer
+	return b
+}
+
+// SetIssuanceDate sets issuanceDate, formatted per RFC 3339.
+func (b *Builder) SetIssuanceDate(t time.Time) *Builder {
+	b.vc.IssuanceDate = t.UTC().Format(time.RFC3339)
+	return b
+}
+
+// SetExpirationDate sets expirationDate, formatted per RFC 3339.
+func (b *Builder) SetExpirationDate(t time.Time) *Builder {
+	b.vc.ExpirationDate = t.UTC().Format(time.RFC3339)
+	return b
+}
+
+// SetCredentialSubject sets the credentialSubject claims.
+func (b *Builder) SetCredentialSubject(subject map[string]interface{}) *Builder {
+	b.vc.CredentialSubject = subject
+	return b
+}
+
+// SetCredentialStatus attaches a revocation reference.
+func (b *Builder) SetCredentialStatus(status *CredentialStatus) *Builder {
+	b.vc.CredentialStatus = status
+	return b
+}
+
+// Build returns the credential as constructed so far, without attaching a
+// proof. Used when the proof is supplied separately, e.g. a signature
+// produced off-chain and passed in as a transaction argument.
+func (b *Builder) Build() *VerifiableCredential {
+	credential := b.vc
+	return &credential
+}
+
+// Sign canonicalizes the credential built so far and attaches an
+// Ed25519Signature2020 proof over it, signed by signer.
+func (b *Builder) Sign(signer ed25519.PrivateKey, verificationMethod string) (*VerifiableCredential, error) {
+	canonical, err := Canonicalize(b.vc)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := b.vc
+	credential.Proof = &Proof{
+		Type:               ProofTypeEd25519Signature2020,
+		Created:            time.Now().UTC().Format(time.RFC3339),
+		VerificationMethod: verificationMethod,
+		ProofPurpose:       ProofPurposeAssertionMethod,
+		ProofValue:         base64.StdEncoding.EncodeToString(ed25519.Sign(signer, canonical)),
+	}
+
+	return &credential, nil
+}
+
+// Canonicalize returns the shim.CanonicalJSON serialization (object keys
+// sorted, no insignificant whitespace) of a credential with its proof and
+// credentialStatus stripped. Strings are escaped as encoding/json escapes
+// them (<, >, & and U+2028/U+2029 become \u escapes), so for such strings
+// the output differs from RFC 8785 JCS. This is what issuers sign and
+// verifiers recompute, standing in for full URDNA2015 JSON-LD
+// normalization until this package grows an RDF dataset normalizer.
+// credentialStatus is excluded because its statusListIndex is allocated by
+// the chaincode at issuance time, after the issuer has already signed;
+// unlike the claims, the network trusts the chaincode (not the issuer's
+// signature) to assign it correctly.
+func Canonicalize(credential VerifiableCredential) ([]byte, error) {
+	credential.Proof = nil
+	credential.CredentialStatus = nil
+
+	canonical, err := shim.CanonicalJSON(credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize credential: %w", err)
+	}
+
+	return canonical, nil
+}
+
+// VerifyProof reports whether credential.Proof is a valid
+// Ed25519Signature2020 signature over the credential's canonical form,
+// under issuerPublicKey, and that it was produced by the verification
+// method the caller expects (normally derived from the issuer's
+// registered identity).
+func VerifyProof(credential *VerifiableCredential, issuerPublicKey, expectedVerificationMethod string) (bool, error) {
+	if credential.Proof == nil {
+		return false, fmt.Errorf("credential has no proof")
+	}
+	if credential.Proof.Type != ProofTypeEd25519Signature2020 {
+		return false, fmt.Errorf("unsupported proof type: %s", credential.Proof.Type)
+	}
+	if credential.Proof.VerificationMethod != expectedVerificationMethod {
+		return false, fmt.Errorf("proof verificationMethod %q does not match issuer's registered key %q", credential.Proof.VerificationMethod, expectedVerificationMethod)
+	}
+
+	signature, err := shim.DecodeSignature(credential.Proof.ProofValue)
+	if err != nil {
+		return false, err
+	}
+
+	canonical, err := Canonicalize(*credential)
+	if err != nil {
+		return false, err
+	}
+
+	return shim.VerifySignature(issuerPublicKey, shim.AlgorithmEd25519, canonical, signature)
+}
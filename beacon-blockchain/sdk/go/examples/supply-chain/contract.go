@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+	"github.com/beacon-blockchain/sdk-go/shim/contractapi"
+)
+
+// responseOK is the shim.Response status code for a successful invocation.
+const responseOK = 200
+
+// SupplyChainContract is the typed contractapi front end for the supply
+// chain chaincode. Each method is a strongly-typed transaction that
+// delegates to the existing legacy-dispatch logic in SupplyChainChaincode
+// rather than duplicating it, so the underlying state model and access
+// control (requireRole, validateTransition, recordProvenanceResponse)
+// keep working exactly as they do invoked through the legacy Invoke path.
+type SupplyChainContract struct {
+	contractapi.Contract
+	legacy SupplyChainChaincode
+}
+
+// CreateProductInput carries the fields needed to register a new product.
+type CreateProductInput struct {
+	ProductID    string `json:"productID"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	SKU          string `json:"sku"`
+	Manufacturer string `json:"manufacturer"`
+}
+
+// CreateProduct registers a new product and records its creation in the
+// provenance chain. Requires the caller to hold the manufacturer role.
+func (c *SupplyChainContract) CreateProduct(ctx *contractapi.TransactionContext, input CreateProductInput) (*Product, error) {
+	resp := c.legacy.createProduct(ctx.GetStub(), []string{
+		input.ProductID, input.Name, input.Description, input.SKU, input.Manufacturer,
+	})
+	if resp.Status != responseOK {
+		return nil, errors.New(resp.Message)
+	}
+
+	var product Product
+	if err := shim.GetStateAsJSON(ctx.GetStub(), "product:"+input.ProductID, &product); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// GetProduct returns the product registered under the given ID.
+func (c *SupplyChainContract) GetProduct(ctx *contractapi.TransactionContext, productID string) (*Product, error) {
+	resp := c.legacy.getProduct(ctx.GetStub(), []string{productID})
+	if resp.Status != responseOK {
+		return nil, errors.New(resp.Message)
+	}
+
+	var product Product
+	if err := shim.GetStateAsJSON(ctx.GetStub(), "product:"+productID, &product); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// RecordProvenanceInput carries the fields needed to append a provenance
+// event for a product. Collection is optional; when set, Evidence is
+// committed privately and only its hash is recorded on-chain.
+type RecordProvenanceInput struct {
+	ProductID  string                 `json:"productID"`
+	Action     string                 `json:"action"`
+	Actor      string                 `json:"actor"`
+	Location   string                 `json:"location"`
+	Evidence   map[string]interface{} `json:"evidence"`
+	Collection string                 `json:"collection,omitempty"`
+}
+
+// RecordProvenance appends a provenance event for a product.
+func (c *SupplyChainContract) RecordProvenance(ctx *contractapi.TransactionContext, input RecordProvenanceInput) (*ProvenanceRecord, error) {
+	if _, err := requireRole(ctx.GetStub(), roleManufacturer); err != nil {
+		return nil, err
+	}
+
+	resp := c.legacy.recordProvenanceResponse(ctx.GetStub(), input.ProductID, input.Action, input.Actor, input.Location, input.Evidence, input.Collection)
+	if resp.Status != responseOK {
+		return nil, errors.New(resp.Message)
+	}
+
+	provenanceID := strings.TrimPrefix(string(resp.Payload), "Provenance recorded: ")
+
+	var record ProvenanceRecord
+	if err := shim.GetStateAsJSON(ctx.GetStub(), provenanceID, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// VerifyProvenance marks a provenance record as verified by an auditor.
+// Requires the caller to hold the auditor role.
+func (c *SupplyChainContract) VerifyProvenance(ctx *contractapi.TransactionContext, provenanceID string, verifier string) error {
+	resp := c.legacy.verifyProvenance(ctx.GetStub(), []string{provenanceID, verifier})
+	if resp.Status != responseOK {
+		return errors.New(resp.Message)
+	}
+
+	return nil
+}
+
+// TransferCustodyInput carries the fields needed to hand a shipment off
+// to its recipient, transferring custody of the product it carries.
+type TransferCustodyInput struct {
+	ShipmentID string `json:"shipmentID"`
+	Recipient  string `json:"recipient"`
+}
+
+// TransferCustody marks a shipment delivered, transferring custody of the
+// product it carries to the named recipient. Requires the caller to hold
+// the recipient role.
+func (c *SupplyChainContract) TransferCustody(ctx *contractapi.TransactionContext, input TransferCustodyInput) error {
+	resp := c.legacy.deliverShipment(ctx.GetStub(), []string{input.ShipmentID, input.Recipient})
+	if resp.Status != responseOK {
+		return errors.New(resp.Message)
+	}
+
+	return nil
+}
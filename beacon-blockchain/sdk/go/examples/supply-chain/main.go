@@ -1,16 +1,26 @@
 package main
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/beacon-blockchain/sdk-go/shim"
+	"github.com/beacon-blockchain/sdk-go/shim/contractapi"
 )
 
-// SupplyChainChaincode implements tracking and provenance for supply chain management
+// SupplyChainChaincode implements tracking and provenance for supply chain
+// management. queryProvenance's rich Mango selectors require the peer's
+// state database to be CouchDB (not LevelDB); the indexes it relies on are
+// shipped alongside this package in META-INF/statedb/couchdb/indexes/.
 type SupplyChainChaincode struct{}
 
 // Product represents a product in the supply chain
@@ -26,6 +36,30 @@ type Product struct {
 	Metadata     map[string]interface{} `json:"metadata"`
 }
 
+// ProductPrivateData holds the commercially sensitive side of a product —
+// cost basis, supplier pricing, buyer identity — that must stay within the
+// manufacturer's org while the public Product record (name, SKU, status)
+// remains visible to every channel member for provenance purposes. Stored
+// in a named private data collection under the same key as the public
+// product, not on the public ledger.
+type ProductPrivateData struct {
+	ProductID    string                 `json:"productID"`
+	UnitCost     float64                `json:"unitCost,omitempty"`
+	Currency     string                 `json:"currency,omitempty"`
+	SupplierID   string                 `json:"supplierID,omitempty"`
+	BuyerID      string                 `json:"buyerID,omitempty"`
+	ContractTerm string                 `json:"contractTerm,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// manufacturerPrivateCollection is the default private data collection for
+// product cost and supplier/buyer identity.
+const manufacturerPrivateCollection = "manufacturerPrivate"
+
+// buyerSellerPrivateCollection is the default private data collection for
+// commercial transaction terms shared between exactly the two counterparties.
+const buyerSellerPrivateCollection = "buyerSellerPrivate"
+
 // Shipment represents a shipment in the supply chain
 type Shipment struct {
 	ID           string                 `json:"id"`
@@ -56,8 +90,73 @@ type Transaction struct {
 	TxHash      string                 `json:"txHash"`
 }
 
+// Escrow locks a Transaction's funds until the shipment it pays for is
+// confirmed delivered, turning recordTransaction's plain bookkeeping into a
+// real payment-on-delivery flow.
+type Escrow struct {
+	TransactionID string  `json:"transactionID"`
+	Buyer         string  `json:"buyer"`
+	Seller        string  `json:"seller"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	ShipmentID    string  `json:"shipmentID"`
+	Status        string  `json:"status"`
+	Reason        string  `json:"reason,omitempty"`
+	CreatedAt     int64   `json:"createdAt"`
+	ResolvedAt    int64   `json:"resolvedAt,omitempty"`
+}
+
+// TransactionPrivateData holds the commercial terms of a transaction —
+// negotiated price, payment terms, buyer/seller identities — kept in a
+// collection shared only between the counterparties, alongside the public
+// Transaction record that the rest of the channel can still audit.
+type TransactionPrivateData struct {
+	TransactionID string                 `json:"transactionID"`
+	Price         float64                `json:"price,omitempty"`
+	Currency      string                 `json:"currency,omitempty"`
+	PaymentTerms  string                 `json:"paymentTerms,omitempty"`
+	BuyerID       string                 `json:"buyerID,omitempty"`
+	SellerID      string                 `json:"sellerID,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata"`
+}
+
+// ComponentLink is one edge in a product's bill of materials: a quantity
+// of a child product or raw material consumed to produce a parent,
+// optionally tied to the specific batch it was drawn from. Stored under
+// bom:<parentID>:<childID>, keyed by parent so a product's direct BOM can
+// be range-scanned.
+type ComponentLink struct {
+	ParentID string                 `json:"parentID"`
+	ChildID  string                 `json:"childID"`
+	Quantity float64                `json:"quantity"`
+	Unit     string                 `json:"unit"`
+	BatchID  string                 `json:"batchID,omitempty"`
+	AddedAt  int64                  `json:"addedAt"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// BOMNode is one node of a bill-of-materials trace: a product plus the
+// component links leading to or from it, recursively expanded up to the
+// trace's requested depth. Truncated marks a node where expansion stopped
+// early, either because maxDepth was reached or because the product was
+// already on the current path (a cyclic BOM).
+type BOMNode struct {
+	ProductID string     `json:"productID"`
+	Quantity  float64    `json:"quantity,omitempty"`
+	Unit      string     `json:"unit,omitempty"`
+	BatchID   string     `json:"batchID,omitempty"`
+	Children  []*BOMNode `json:"children,omitempty"`
+	Truncated bool       `json:"truncated,omitempty"`
+}
+
+// defaultTraceDepth bounds recursive BOM traversal when the caller doesn't
+// specify a max depth, protecting against runaway recursion over a deep or
+// cyclic graph.
+const defaultTraceDepth = 10
+
 // ProvenanceRecord represents a provenance record
 type ProvenanceRecord struct {
+	DocType   string                 `json:"docType"`
 	ID        string                 `json:"id"`
 	ProductID string                 `json:"productID"`
 	Action    string                 `json:"action"`
@@ -69,6 +168,532 @@ type ProvenanceRecord struct {
 	TxHash    string                 `json:"txHash"`
 }
 
+// provenanceDocType is the Mango selector docType for every ProvenanceRecord,
+// following the docType convention of the Oracle and IBM foodchain
+// chaincode samples so rich queries can select on {"docType":"provenance", ...}
+// instead of a full ledger scan.
+const provenanceDocType = "provenance"
+
+// EvidenceAnchor cryptographically binds an off-chain artifact (a lab
+// certificate, photo, or IoT sensor log) to an on-chain provenance record,
+// so bulky evidence can live off-chain while still being tamper-evident.
+type EvidenceAnchor struct {
+	ID           string `json:"id"`
+	ProvenanceID string `json:"provenanceID"`
+	URI          string `json:"uri"`
+	SHA256       string `json:"sha256"`
+	MediaType    string `json:"mediaType"`
+	SignerID     string `json:"signerID"`
+	Signature    string `json:"signature"`
+	SignatureAlg string `json:"signatureAlg"`
+	AttachedAt   int64  `json:"attachedAt"`
+}
+
+// Certificate is a digital attestation issued by a certifying org (e.g. an
+// organic or fair-trade auditor) about a product or actor in the supply
+// chain, modeled after the Certificate asset in Oracle's education
+// chaincode sample. Status is "active" until revokeCertificate marks it
+// "revoked"; a revoked certificate can no longer back a provenance record.
+type Certificate struct {
+	ID        string `json:"id"`
+	Issuer    string `json:"issuer"`
+	ProductID string `json:"productID"`
+	Actor     string `json:"actor"`
+	Industry  string `json:"industry"`
+	ImageURL  string `json:"imageURL"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// Composite-key indexes back the lookups that used to scan an entire key
+// prefix and filter in Go — listProducts, trackShipment,
+// getProductTransactions, getProductProvenance, and traceProduct — which
+// stops scaling once a collection holds more than a few thousand entries.
+const (
+	shipmentByTrackingIndex   = "idx:shipmentByTracking"
+	shipmentByProductIndex    = "idx:shipmentByProduct"
+	txByProductIndex          = "idx:txByProduct"
+	provenanceByProductIndex  = "idx:provenanceByProduct"
+	productByCategoryIndex    = "idx:productByCategory"
+	evidenceByProvenanceIndex = "idx:evidenceByProvenance"
+	certificateByProductIndex = "idx:certificateByProduct"
+)
+
+// timestampIndexKey zero-pads a Unix timestamp so a composite-key range
+// scan returns entries in chronological order.
+func timestampIndexKey(ts int64) string {
+	return fmt.Sprintf("%020d", ts)
+}
+
+// indexShipmentByTracking records shipmentID under its tracking ID so
+// trackShipment can look it up directly instead of scanning every shipment.
+func indexShipmentByTracking(stub shim.ChaincodeStubInterface, trackingID, shipmentID string) error {
+	key, err := shim.CreateCompositeKey(shipmentByTrackingIndex, []string{trackingID, shipmentID})
+	if err != nil {
+		return fmt.Errorf("failed to create shipment tracking index key: %w", err)
+	}
+	return stub.PutState(key, []byte(shipmentID))
+}
+
+// indexShipmentByProduct records shipmentID under productID so
+// traceProduct can range-scan a product's shipments instead of scanning
+// every shipment in the ledger.
+func indexShipmentByProduct(stub shim.ChaincodeStubInterface, productID, shipmentID string) error {
+	key, err := shim.CreateCompositeKey(shipmentByProductIndex, []string{productID, shipmentID})
+	if err != nil {
+		return fmt.Errorf("failed to create shipment product index key: %w", err)
+	}
+	return stub.PutState(key, []byte(shipmentID))
+}
+
+// indexTransactionByProduct records transactionID under productID and a
+// zero-padded timestamp so getProductTransactions can range-scan a
+// product's transactions in chronological order.
+func indexTransactionByProduct(stub shim.ChaincodeStubInterface, productID string, timestamp int64, transactionID string) error {
+	key, err := shim.CreateCompositeKey(txByProductIndex, []string{productID, timestampIndexKey(timestamp), transactionID})
+	if err != nil {
+		return fmt.Errorf("failed to create transaction index key: %w", err)
+	}
+	return stub.PutState(key, []byte(transactionID))
+}
+
+// indexProvenanceByProduct records provenanceID under productID and a
+// zero-padded timestamp so getProductProvenance can range-scan a product's
+// provenance chain in chronological order.
+func indexProvenanceByProduct(stub shim.ChaincodeStubInterface, productID string, timestamp int64, provenanceID string) error {
+	key, err := shim.CreateCompositeKey(provenanceByProductIndex, []string{productID, timestampIndexKey(timestamp), provenanceID})
+	if err != nil {
+		return fmt.Errorf("failed to create provenance index key: %w", err)
+	}
+	return stub.PutState(key, []byte(provenanceID))
+}
+
+// indexEvidenceByProvenance records anchorID under its provenanceID so
+// verifyEvidence can range-scan the anchors attached to a provenance record
+// instead of scanning every anchor ever attached.
+func indexEvidenceByProvenance(stub shim.ChaincodeStubInterface, provenanceID, anchorID string) error {
+	key, err := shim.CreateCompositeKey(evidenceByProvenanceIndex, []string{provenanceID, anchorID})
+	if err != nil {
+		return fmt.Errorf("failed to create evidence index key: %w", err)
+	}
+	return stub.PutState(key, []byte(anchorID))
+}
+
+// indexProductByCategory records productID under its category so
+// listProducts can range-scan by category instead of filtering every
+// product in Go.
+func indexProductByCategory(stub shim.ChaincodeStubInterface, category, productID string) error {
+	key, err := shim.CreateCompositeKey(productByCategoryIndex, []string{category, productID})
+	if err != nil {
+		return fmt.Errorf("failed to create product category index key: %w", err)
+	}
+	return stub.PutState(key, []byte(productID))
+}
+
+// indexCertificateByProduct records certificateID under productID so
+// getCertificatesForProduct can range-scan a product's certificates instead
+// of scanning every certificate ever issued.
+func indexCertificateByProduct(stub shim.ChaincodeStubInterface, productID, certificateID string) error {
+	key, err := shim.CreateCompositeKey(certificateByProductIndex, []string{productID, certificateID})
+	if err != nil {
+		return fmt.Errorf("failed to create certificate index key: %w", err)
+	}
+	return stub.PutState(key, []byte(certificateID))
+}
+
+// allocateID atomically increments counter:<entity> and returns the next
+// ID in that sequence as "<entity>-<n>", so callers don't have to invent
+// their own unique product/shipment/transaction IDs.
+func allocateID(stub shim.ChaincodeStubInterface, entity string) (string, error) {
+	counterKey := "counter:" + entity
+
+	next, err := shim.GetStateAsInt(stub, counterKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s counter: %w", entity, err)
+	}
+	next++
+
+	if err := shim.PutStateAsInt(stub, counterKey, next); err != nil {
+		return "", fmt.Errorf("failed to store %s counter: %w", entity, err)
+	}
+
+	return fmt.Sprintf("%s-%d", entity, next), nil
+}
+
+// Roles recognized by the RBAC layer. A caller's role is looked up by
+// identity, not hard-coded per function, so the same binary supports many
+// organizations each running their own mix of manufacturers and carriers.
+const (
+	roleManufacturer = "manufacturer"
+	roleCarrier      = "carrier"
+	roleRecipient    = "recipient"
+	roleAuditor      = "auditor"
+	roleAdmin        = "admin"
+)
+
+// roleIndexPrefix is the range-scan prefix over every role:<mspID>:<identityID>
+// key, used to check whether the system has been bootstrapped with an admin.
+const roleIndexPrefix = "role:"
+
+// callerIdentity extracts the transaction creator's MSP ID and identity ID
+// from their X.509 certificate, mirroring the identity resolution used by
+// the gateway-management chaincode's access-policy engine. A creator that
+// isn't a parseable certificate falls back to its MSP ID and the raw
+// creator bytes as its identity ID, so role lookups still have something
+// stable to key on.
+func callerIdentity(stub shim.ChaincodeStubInterface) (mspID, identityID string, err error) {
+	creator, err := stub.GetCreator()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get creator: %w", err)
+	}
+
+	block, _ := pem.Decode(creator)
+	if block == nil {
+		return "unknown", strings.TrimSpace(string(creator)), nil
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "unknown", strings.TrimSpace(string(creator)), nil
+	}
+
+	mspID = "unknown"
+	if len(cert.Issuer.Organization) > 0 {
+		mspID = cert.Issuer.Organization[0]
+	}
+
+	return mspID, cert.Subject.CommonName, nil
+}
+
+// roleKey is the storage key under which an identity's assigned role lives.
+func roleKey(mspID, identityID string) string {
+	return fmt.Sprintf("%s%s:%s", roleIndexPrefix, mspID, identityID)
+}
+
+// callerRole resolves the calling identity's assigned role, returning an
+// empty string (not an error) if no role has been assigned yet.
+func callerRole(stub shim.ChaincodeStubInterface) (mspID, identityID, role string, err error) {
+	mspID, identityID, err = callerIdentity(stub)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	roleBytes, err := stub.GetState(roleKey(mspID, identityID))
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to read role for %s:%s: %w", mspID, identityID, err)
+	}
+
+	return mspID, identityID, string(roleBytes), nil
+}
+
+// anyAdminAssigned reports whether any identity already holds the admin
+// role, so assignRole can let the very first call through to bootstrap the
+// system without requiring an admin to already exist.
+func anyAdminAssigned(stub shim.ChaincodeStubInterface) (bool, error) {
+	iterator, err := stub.GetStateByRange(roleIndexPrefix, roleIndexPrefix+"~")
+	if err != nil {
+		return false, fmt.Errorf("failed to scan roles: %w", err)
+	}
+	defer iterator.Close()
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return false, fmt.Errorf("failed to iterate roles: %w", err)
+		}
+		if string(kv.Value) == roleAdmin {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// requireRole resolves the caller's identity and rejects the call unless
+// their assigned role is one of allowed, returning the identity ID on
+// success so callers can cross-check it (e.g. against a Shipment's Carrier)
+// without re-resolving the creator certificate.
+func requireRole(stub shim.ChaincodeStubInterface, allowed ...string) (identityID string, err error) {
+	mspID, identityID, role, err := callerRole(stub)
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range allowed {
+		if role == r {
+			return identityID, nil
+		}
+	}
+
+	if role == "" {
+		return "", fmt.Errorf("access denied: %s:%s has no assigned role, requires one of %s", mspID, identityID, strings.Join(allowed, ", "))
+	}
+	return "", fmt.Errorf("access denied: %s:%s has role %q, requires one of %s", mspID, identityID, role, strings.Join(allowed, ", "))
+}
+
+// shipmentTransitions and productTransitions declare the legal lifecycle
+// moves for each entity. A target not present in the "from" state's list
+// (or in "*", which applies regardless of current state) is rejected.
+var shipmentTransitions = map[string][]string{
+	"created":          {"in_transit"},
+	"in_transit":       {"out_for_delivery"},
+	"out_for_delivery": {"delivered"},
+	"delivered":        {},
+	"*":                {"exception"},
+}
+
+var productTransitions = map[string][]string{
+	"created":        {"in_production"},
+	"in_production":  {"shipped"},
+	"shipped":        {"received"},
+	"received":       {"sold"},
+	"sold":           {},
+	"*":              {"recalled"},
+}
+
+// transitionError reports an illegal lifecycle move, carrying the entity's
+// current state and the set of states it could legally have moved to so
+// the caller can self-correct instead of guessing.
+type transitionError struct {
+	entity  string
+	from    string
+	to      string
+	allowed []string
+}
+
+func (e *transitionError) Error() string {
+	return fmt.Sprintf("illegal %s transition from %q to %q (allowed: %s)", e.entity, e.from, e.to, strings.Join(e.allowed, ", "))
+}
+
+// validateTransition rejects a from->to lifecycle move that isn't declared
+// in entity's state machine, combining its current-state moves with the
+// wildcard ("*") moves allowed from any state.
+func validateTransition(entity, from, to string) error {
+	var table map[string][]string
+	switch entity {
+	case "shipment":
+		table = shipmentTransitions
+	case "product":
+		table = productTransitions
+	default:
+		return fmt.Errorf("unknown entity %q for transition validation", entity)
+	}
+
+	allowed := append(append([]string{}, table[from]...), table["*"]...)
+	for _, a := range allowed {
+		if a == to {
+			return nil
+		}
+	}
+
+	return &transitionError{entity: entity, from: from, to: to, allowed: allowed}
+}
+
+// nextID allocates and returns the next ID for the given entity (e.g.
+// "product", "shipment", "transaction"), backed by a counter:<entity> key.
+func (cc *SupplyChainChaincode) nextID(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	id, err := allocateID(stub, args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success([]byte(id))
+}
+
+// assignRole grants identityID in mspID one of the recognized roles
+// (manufacturer, carrier, recipient, auditor, admin), gating every
+// role-sensitive invoke. Only an existing admin can assign roles, except
+// for the very first call: with no admin assigned anywhere yet, any caller
+// may bootstrap the system by assigning itself or another identity admin.
+func (cc *SupplyChainChaincode) assignRole(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 3); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	targetMSP := args[0]
+	targetIdentity := args[1]
+	role := args[2]
+
+	switch role {
+	case roleManufacturer, roleCarrier, roleRecipient, roleAuditor, roleAdmin:
+	default:
+		return shim.Error(fmt.Sprintf("Unknown role: %s", role))
+	}
+
+	bootstrapped, err := anyAdminAssigned(stub)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if bootstrapped {
+		if _, err := requireRole(stub, roleAdmin); err != nil {
+			return shim.Error(err.Error())
+		}
+	}
+
+	if err := stub.PutState(roleKey(targetMSP, targetIdentity), []byte(role)); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to assign role: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Role %s assigned to %s:%s", role, targetMSP, targetIdentity)))
+}
+
+// rebuildIndexes re-derives the product, shipment, transaction and
+// provenance composite-key indexes from their underlying records; the
+// evidence-anchor and certificate indexes are not rebuilt. Run this once after
+// upgrading to an index-bearing version of the chaincode, since existing
+// records were written before the indexes existed.
+func (cc *SupplyChainChaincode) rebuildIndexes(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	productCount, err := cc.rebuildProductIndexes(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to rebuild product indexes: %s", err.Error()))
+	}
+
+	shipmentCount, err := cc.rebuildShipmentIndexes(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to rebuild shipment indexes: %s", err.Error()))
+	}
+
+	transactionCount, err := cc.rebuildTransactionIndexes(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to rebuild transaction indexes: %s", err.Error()))
+	}
+
+	provenanceCount, err := cc.rebuildProvenanceIndexes(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to rebuild provenance indexes: %s", err.Error()))
+	}
+
+	summary := map[string]int{
+		"products":     productCount,
+		"shipments":    shipmentCount,
+		"transactions": transactionCount,
+		"provenance":   provenanceCount,
+	}
+
+	responseBytes, err := json.Marshal(summary)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal rebuild summary: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+func (cc *SupplyChainChaincode) rebuildProductIndexes(stub shim.ChaincodeStubInterface) (int, error) {
+	iterator, err := stub.GetStateByRange("product:", "product:~")
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, result := range results {
+		var product Product
+		productBytes, _ := json.Marshal(result.Value)
+		if err := json.Unmarshal(productBytes, &product); err != nil {
+			continue
+		}
+		if err := indexProductByCategory(stub, product.Category, product.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (cc *SupplyChainChaincode) rebuildShipmentIndexes(stub shim.ChaincodeStubInterface) (int, error) {
+	iterator, err := stub.GetStateByRange("shipment:", "shipment:~")
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, result := range results {
+		var shipment Shipment
+		shipmentBytes, _ := json.Marshal(result.Value)
+		if err := json.Unmarshal(shipmentBytes, &shipment); err != nil {
+			continue
+		}
+		if err := indexShipmentByTracking(stub, shipment.TrackingID, shipment.ID); err != nil {
+			return count, err
+		}
+		if err := indexShipmentByProduct(stub, shipment.ProductID, shipment.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (cc *SupplyChainChaincode) rebuildTransactionIndexes(stub shim.ChaincodeStubInterface) (int, error) {
+	iterator, err := stub.GetStateByRange("transaction:", "transaction:~")
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, result := range results {
+		var transaction Transaction
+		transactionBytes, _ := json.Marshal(result.Value)
+		if err := json.Unmarshal(transactionBytes, &transaction); err != nil {
+			continue
+		}
+		if err := indexTransactionByProduct(stub, transaction.ProductID, transaction.Timestamp, transaction.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (cc *SupplyChainChaincode) rebuildProvenanceIndexes(stub shim.ChaincodeStubInterface) (int, error) {
+	iterator, err := stub.GetStateByRange("provenance:", "provenance:~")
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, result := range results {
+		var provenance ProvenanceRecord
+		provenanceBytes, _ := json.Marshal(result.Value)
+		if err := json.Unmarshal(provenanceBytes, &provenance); err != nil {
+			continue
+		}
+		if err := indexProvenanceByProduct(stub, provenance.ProductID, provenance.Timestamp, provenance.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
 // Init initializes the chaincode
 func (cc *SupplyChainChaincode) Init(stub shim.ChaincodeStubInterface) shim.Response {
 	log.Println("Initializing Supply Chain Chaincode")
@@ -125,17 +750,103 @@ func (cc *SupplyChainChaincode) Invoke(stub shim.ChaincodeStubInterface) shim.Re
 		return cc.getTransaction(stub, args)
 	case "getProductTransactions":
 		return cc.getProductTransactions(stub, args)
-	
+
+	// Escrow settlement operations
+	case "deposit":
+		return cc.deposit(stub, args)
+	case "withdraw":
+		return cc.withdraw(stub, args)
+	case "createEscrow":
+		return cc.createEscrow(stub, args)
+	case "releaseEscrow":
+		return cc.releaseEscrow(stub, args)
+	case "refundEscrow":
+		return cc.refundEscrow(stub, args)
+
 	// Provenance operations
 	case "recordProvenance":
 		return cc.recordProvenance(stub, args)
 	case "verifyProvenance":
 		return cc.verifyProvenance(stub, args)
+	case "verifyPrivateEvidence":
+		return cc.verifyPrivateEvidence(stub, args)
 	case "getProductProvenance":
 		return cc.getProductProvenance(stub, args)
 	case "traceProduct":
 		return cc.traceProduct(stub, args)
-	
+
+	// Evidence anchoring operations
+	case "registerSigner":
+		return cc.registerSigner(stub, args)
+	case "attachEvidence":
+		return cc.attachEvidence(stub, args)
+	case "verifyEvidence":
+		return cc.verifyEvidence(stub, args)
+
+	// Certificate operations
+	case "issueCertificate":
+		return cc.issueCertificate(stub, args)
+	case "revokeCertificate":
+		return cc.revokeCertificate(stub, args)
+	case "getCertificatesForProduct":
+		return cc.getCertificatesForProduct(stub, args)
+	case "attachCertificateToProvenance":
+		return cc.attachCertificateToProvenance(stub, args)
+
+	// Bill of materials operations
+	case "addComponent":
+		return cc.addComponent(stub, args)
+	case "removeComponent":
+		return cc.removeComponent(stub, args)
+	case "getBOM":
+		return cc.getBOM(stub, args)
+	case "traceUpstream":
+		return cc.traceUpstream(stub, args)
+	case "traceDownstream":
+		return cc.traceDownstream(stub, args)
+	case "getProductsContainingComponent":
+		return cc.getProductsContainingComponent(stub, args)
+
+	// Private data operations
+	case "createProductPrivate":
+		return cc.createProductPrivate(stub, args)
+	case "getProductPrivate":
+		return cc.getProductPrivate(stub, args)
+	case "sharePrivateAttribute":
+		return cc.sharePrivateAttribute(stub, args)
+
+	// Index maintenance
+	case "rebuildIndexes":
+		return cc.rebuildIndexes(stub, args)
+
+	// ID allocation
+	case "nextID":
+		return cc.nextID(stub, args)
+
+	// Access control
+	case "assignRole":
+		return cc.assignRole(stub, args)
+
+	// History queries
+	case "getProductHistory":
+		return cc.getProductHistory(stub, args)
+	case "getShipmentHistory":
+		return cc.getShipmentHistory(stub, args)
+	case "getProvenanceRecordHistory":
+		return cc.getProvenanceRecordHistory(stub, args)
+	case "getProvenanceHistory":
+		return cc.getProvenanceHistory(stub, args)
+	case "getProvenanceBetween":
+		return cc.getProvenanceBetween(stub, args)
+	case "queryProvenance":
+		return cc.queryProvenance(stub, args)
+	case "invokeExternalProvenance":
+		return cc.invokeExternalProvenance(stub, args)
+	case "verifyCrossChainLink":
+		return cc.verifyCrossChainLink(stub, args)
+	case "traverseIngredients":
+		return cc.traverseIngredients(stub, args)
+
 	default:
 		return shim.Error(fmt.Sprintf("Unknown function: %s", function))
 	}
@@ -146,13 +857,25 @@ func (cc *SupplyChainChaincode) createProduct(stub shim.ChaincodeStubInterface,
 	if err := shim.ValidateArgs(args, 5); err != nil {
 		return shim.Error(err.Error())
 	}
-	
+
+	if _, err := requireRole(stub, roleManufacturer); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	productID := args[0]
 	name := args[1]
 	description := args[2]
 	sku := args[3]
 	manufacturer := args[4]
-	
+
+	if productID == "" {
+		allocated, err := allocateID(stub, "product")
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to allocate product ID: %s", err.Error()))
+		}
+		productID = allocated
+	}
+
 	// Check if product already exists
 	existing, err := stub.GetState("product:" + productID)
 	if err != nil {
@@ -179,7 +902,11 @@ func (cc *SupplyChainChaincode) createProduct(stub shim.ChaincodeStubInterface,
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to store product: %s", err.Error()))
 	}
-	
+
+	if err := indexProductByCategory(stub, product.Category, productID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index product: %s", err.Error()))
+	}
+
 	// Record provenance
 	cc.recordProvenanceInternal(stub, productID, "CREATE", manufacturer, "factory", map[string]interface{}{
 		"sku": sku,
@@ -204,16 +931,24 @@ func (cc *SupplyChainChaincode) updateProduct(stub shim.ChaincodeStubInterface,
 		return shim.Error(err.Error())
 	}
 	
+	if _, err := requireRole(stub, roleManufacturer); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	productID := args[0]
 	status := args[1]
-	
+
 	// Get existing product
 	var product Product
 	err := shim.GetStateAsJSON(stub, "product:"+productID, &product)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Product not found: %s", productID))
 	}
-	
+
+	if err := validateTransition("product", product.Status, status); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	// Update status
 	product.Status = status
 	
@@ -266,33 +1001,61 @@ func (cc *SupplyChainChaincode) listProducts(stub shim.ChaincodeStubInterface, a
 	if len(args) > 0 {
 		categoryFilter = args[0]
 	}
-	
+
+	// With a category given, walk the productByCategory index directly
+	// instead of scanning every product in the ledger.
+	if categoryFilter != "" {
+		iterator, err := stub.GetStateByPartialCompositeKey(productByCategoryIndex, []string{categoryFilter})
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to query product category index: %s", err.Error()))
+		}
+		defer iterator.Close()
+
+		var filteredProducts []Product
+		for iterator.HasNext() {
+			kv, err := iterator.Next()
+			if err != nil {
+				return shim.Error(fmt.Sprintf("Failed to iterate product category index: %s", err.Error()))
+			}
+
+			var product Product
+			if err := shim.GetStateAsJSON(stub, "product:"+string(kv.Value), &product); err != nil {
+				continue
+			}
+			filteredProducts = append(filteredProducts, product)
+		}
+
+		responseBytes, err := json.Marshal(filteredProducts)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+		}
+		return shim.Success(responseBytes)
+	}
+
 	iterator, err := stub.GetStateByRange("product:", "product:~")
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to get products: %s", err.Error()))
 	}
-	
+
 	results, err := shim.IteratorToArray(iterator)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
 	}
-	
+
 	var filteredProducts []Product
 	for _, result := range results {
 		var product Product
 		productBytes, _ := json.Marshal(result.Value)
 		json.Unmarshal(productBytes, &product)
-		
-		if categoryFilter == "" || product.Category == categoryFilter {
-			filteredProducts = append(filteredProducts, product)
-		}
+
+		filteredProducts = append(filteredProducts, product)
 	}
-	
+
 	responseBytes, err := json.Marshal(filteredProducts)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
 	}
-	
+
 	return shim.Success(responseBytes)
 }
 
@@ -301,14 +1064,26 @@ func (cc *SupplyChainChaincode) createShipment(stub shim.ChaincodeStubInterface,
 	if err := shim.ValidateArgs(args, 6); err != nil {
 		return shim.Error(err.Error())
 	}
-	
+
+	if _, err := requireRole(stub, roleCarrier); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	shipmentID := args[0]
 	productID := args[1]
 	fromLocation := args[2]
 	toLocation := args[3]
 	carrier := args[4]
 	trackingID := args[5]
-	
+
+	if shipmentID == "" {
+		allocated, err := allocateID(stub, "shipment")
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to allocate shipment ID: %s", err.Error()))
+		}
+		shipmentID = allocated
+	}
+
 	// Verify product exists
 	var product Product
 	err := shim.GetStateAsJSON(stub, "product:"+productID, &product)
@@ -334,7 +1109,14 @@ func (cc *SupplyChainChaincode) createShipment(stub shim.ChaincodeStubInterface,
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to store shipment: %s", err.Error()))
 	}
-	
+
+	if err := indexShipmentByTracking(stub, trackingID, shipmentID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index shipment: %s", err.Error()))
+	}
+	if err := indexShipmentByProduct(stub, productID, shipmentID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index shipment: %s", err.Error()))
+	}
+
 	// Record provenance
 	cc.recordProvenanceInternal(stub, productID, "SHIP", carrier, fromLocation, map[string]interface{}{
 		"shipmentID": shipmentID,
@@ -364,14 +1146,27 @@ func (cc *SupplyChainChaincode) updateShipmentStatus(stub shim.ChaincodeStubInte
 	shipmentID := args[0]
 	status := args[1]
 	location := args[2]
-	
+
+	carrierIdentity, err := requireRole(stub, roleCarrier)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
 	// Get existing shipment
 	var shipment Shipment
-	err := shim.GetStateAsJSON(stub, "shipment:"+shipmentID, &shipment)
+	err = shim.GetStateAsJSON(stub, "shipment:"+shipmentID, &shipment)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Shipment not found: %s", shipmentID))
 	}
-	
+
+	if shipment.Carrier != carrierIdentity {
+		return shim.Error(fmt.Sprintf("Access denied: %s is not the carrier of record (%s) for shipment %s", carrierIdentity, shipment.Carrier, shipmentID))
+	}
+
+	if err := validateTransition("shipment", shipment.Status, status); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	// Update status
 	shipment.Status = status
 	if shipment.Metadata == nil {
@@ -403,14 +1198,22 @@ func (cc *SupplyChainChaincode) deliverShipment(stub shim.ChaincodeStubInterface
 	
 	shipmentID := args[0]
 	recipient := args[1]
-	
+
+	if _, err := requireRole(stub, roleRecipient); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	// Get existing shipment
 	var shipment Shipment
 	err := shim.GetStateAsJSON(stub, "shipment:"+shipmentID, &shipment)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Shipment not found: %s", shipmentID))
 	}
-	
+
+	if err := validateTransition("shipment", shipment.Status, "delivered"); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	// Update delivery status
 	shipment.Status = "delivered"
 	shipment.DeliveredAt = time.Now().Unix()
@@ -470,30 +1273,35 @@ func (cc *SupplyChainChaincode) trackShipment(stub shim.ChaincodeStubInterface,
 	}
 	
 	trackingID := args[0]
-	
-	// Search for shipment by tracking ID
-	iterator, err := stub.GetStateByRange("shipment:", "shipment:~")
+
+	// Look up the shipment by tracking ID directly via the index instead
+	// of scanning every shipment.
+	iterator, err := stub.GetStateByPartialCompositeKey(shipmentByTrackingIndex, []string{trackingID})
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to search shipments: %s", err.Error()))
 	}
-	
-	results, err := shim.IteratorToArray(iterator)
+	defer iterator.Close()
+
+	if !iterator.HasNext() {
+		return shim.Error(fmt.Sprintf("Shipment with tracking ID %s not found", trackingID))
+	}
+
+	kv, err := iterator.Next()
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Failed to read shipment tracking index: %s", err.Error()))
 	}
-	
-	for _, result := range results {
-		var shipment Shipment
-		shipmentBytes, _ := json.Marshal(result.Value)
-		json.Unmarshal(shipmentBytes, &shipment)
-		
-		if shipment.TrackingID == trackingID {
-			responseBytes, _ := json.Marshal(shipment)
-			return shim.Success(responseBytes)
-		}
+
+	var shipment Shipment
+	if err := shim.GetStateAsJSON(stub, "shipment:"+string(kv.Value), &shipment); err != nil {
+		return shim.Error(fmt.Sprintf("Shipment with tracking ID %s not found", trackingID))
 	}
-	
-	return shim.Error(fmt.Sprintf("Shipment with tracking ID %s not found", trackingID))
+
+	responseBytes, err := json.Marshal(shipment)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal shipment: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
 }
 
 // recordTransaction records a transaction
@@ -508,7 +1316,15 @@ func (cc *SupplyChainChaincode) recordTransaction(stub shim.ChaincodeStubInterfa
 	from := args[3]
 	to := args[4]
 	status := args[5]
-	
+
+	if transactionID == "" {
+		allocated, err := allocateID(stub, "transaction")
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to allocate transaction ID: %s", err.Error()))
+		}
+		transactionID = allocated
+	}
+
 	var amount float64
 	var currency string
 	
@@ -543,233 +1359,1778 @@ func (cc *SupplyChainChaincode) recordTransaction(stub shim.ChaincodeStubInterfa
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to store transaction: %s", err.Error()))
 	}
-	
+
+	if err := indexTransactionByProduct(stub, productID, transaction.Timestamp, transactionID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index transaction: %s", err.Error()))
+	}
+
 	return shim.Success([]byte(fmt.Sprintf("Transaction %s recorded successfully", transactionID)))
 }
 
-// getTransaction retrieves a transaction by ID
-func (cc *SupplyChainChaincode) getTransaction(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 1); err != nil {
-		return shim.Error(err.Error())
+// recordTransactionInternal allocates a transaction ID and writes a
+// Transaction record, the same way recordProvenanceInternal does for
+// provenance — used by the escrow settlement flow to log each lock,
+// release, or refund as a first-class transaction.
+func (cc *SupplyChainChaincode) recordTransactionInternal(stub shim.ChaincodeStubInterface, productID, shipmentID, txType, from, to, status string, amount float64, currency string) (string, error) {
+	transactionID, err := allocateID(stub, "transaction")
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate transaction ID: %w", err)
 	}
-	
-	transactionID := args[0]
-	
-	transactionBytes, err := stub.GetState("transaction:" + transactionID)
+
+	transaction := Transaction{
+		ID:         transactionID,
+		ProductID:  productID,
+		ShipmentID: shipmentID,
+		Type:       txType,
+		From:       from,
+		To:         to,
+		Timestamp:  time.Now().Unix(),
+		Amount:     amount,
+		Currency:   currency,
+		Status:     status,
+		Metadata:   make(map[string]interface{}),
+		TxHash:     stub.GetTxID(),
+	}
+
+	if err := shim.PutStateAsJSON(stub, "transaction:"+transactionID, transaction); err != nil {
+		return "", fmt.Errorf("failed to store transaction: %w", err)
+	}
+
+	if err := indexTransactionByProduct(stub, productID, transaction.Timestamp, transactionID); err != nil {
+		return "", fmt.Errorf("failed to index transaction: %w", err)
+	}
+
+	return transactionID, nil
+}
+
+// getBalance returns account's current balance in currency, tracked under
+// balance:<account>:<currency>.
+func getBalance(stub shim.ChaincodeStubInterface, account, currency string) (float64, error) {
+	value, err := shim.GetStateAsString(stub, "balance:"+account+":"+currency)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to get transaction: %s", err.Error()))
+		return 0, err
 	}
-	if transactionBytes == nil {
-		return shim.Error(fmt.Sprintf("Transaction not found: %s", transactionID))
+	if value == "" {
+		return 0, nil
 	}
-	
-	return shim.Success(transactionBytes)
+	return strconv.ParseFloat(value, 64)
 }
 
-// getProductTransactions retrieves all transactions for a product
-func (cc *SupplyChainChaincode) getProductTransactions(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 1); err != nil {
+// putBalance stores account's balance in currency.
+func putBalance(stub shim.ChaincodeStubInterface, account, currency string, balance float64) error {
+	return shim.PutStateAsString(stub, "balance:"+account+":"+currency, strconv.FormatFloat(balance, 'f', -1, 64))
+}
+
+// deposit credits amount in currency to account's internal ledger balance.
+func (cc *SupplyChainChaincode) deposit(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 3); err != nil {
 		return shim.Error(err.Error())
 	}
-	
-	productID := args[0]
-	
-	iterator, err := stub.GetStateByRange("transaction:", "transaction:~")
-	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to get transactions: %s", err.Error()))
+
+	// Crediting a balance with no on-chain payment to back it is only
+	// trustworthy coming from an admin who has verified the off-chain
+	// funding event; an arbitrary caller can't self-mint funds.
+	if _, err := requireRole(stub, roleAdmin); err != nil {
+		return shim.Error(err.Error())
 	}
-	
-	results, err := shim.IteratorToArray(iterator)
+
+	account := args[0]
+	amount, err := strconv.ParseFloat(args[1], 64)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Invalid amount: %s", args[1]))
 	}
-	
-	var productTransactions []Transaction
-	for _, result := range results {
-		var transaction Transaction
-		transactionBytes, _ := json.Marshal(result.Value)
-		json.Unmarshal(transactionBytes, &transaction)
-		
-		if transaction.ProductID == productID {
-			productTransactions = append(productTransactions, transaction)
-		}
+	if amount <= 0 {
+		return shim.Error("Amount must be positive")
 	}
-	
-	responseBytes, err := json.Marshal(productTransactions)
+	currency := args[2]
+
+	balance, err := getBalance(stub, account, currency)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Failed to read balance: %s", err.Error()))
 	}
-	
-	return shim.Success(responseBytes)
+
+	if err := putBalance(stub, account, currency, balance+amount); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to deposit: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Deposited %.2f %s to %s", amount, currency, account)))
 }
 
-// recordProvenance records a provenance entry
-func (cc *SupplyChainChaincode) recordProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 5); err != nil {
+// withdraw debits amount in currency from account's internal ledger
+// balance, failing if funds are insufficient.
+func (cc *SupplyChainChaincode) withdraw(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 3); err != nil {
 		return shim.Error(err.Error())
 	}
-	
-	productID := args[0]
-	action := args[1]
-	actor := args[2]
-	location := args[3]
-	evidenceJSON := args[4]
-	
-	// Parse evidence
-	var evidence map[string]interface{}
-	err := json.Unmarshal([]byte(evidenceJSON), &evidence)
-	if err != nil {
-		return shim.Error(fmt.Sprintf("Invalid evidence JSON: %s", err.Error()))
-	}
-	
-	return cc.recordProvenanceResponse(stub, productID, action, actor, location, evidence)
-}
 
-// verifyProvenance verifies a provenance record
-func (cc *SupplyChainChaincode) verifyProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 2); err != nil {
+	account := args[0]
+
+	if _, identityID, err := callerIdentity(stub); err != nil {
 		return shim.Error(err.Error())
+	} else if identityID != account {
+		return shim.Error(fmt.Sprintf("Access denied: %s cannot withdraw from account %s", identityID, account))
 	}
-	
-	provenanceID := args[0]
-	verifier := args[1]
-	
-	// Get provenance record
-	var provenance ProvenanceRecord
-	err := shim.GetStateAsJSON(stub, provenanceID, &provenance)
+
+	amount, err := strconv.ParseFloat(args[1], 64)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+		return shim.Error(fmt.Sprintf("Invalid amount: %s", args[1]))
 	}
-	
-	// Update verification status
-	provenance.Verified = true
-	if provenance.Evidence == nil {
-		provenance.Evidence = make(map[string]interface{})
+	if amount <= 0 {
+		return shim.Error("Amount must be positive")
 	}
-	provenance.Evidence["verifiedBy"] = verifier
-	provenance.Evidence["verifiedAt"] = time.Now().Unix()
-	
-	// Store updated provenance
-	err = shim.PutStateAsJSON(stub, provenanceID, provenance)
+	currency := args[2]
+
+	balance, err := getBalance(stub, account, currency)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to verify provenance: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Failed to read balance: %s", err.Error()))
 	}
-	
-	return shim.Success([]byte(fmt.Sprintf("Provenance %s verified successfully", provenanceID)))
+	if balance < amount {
+		return shim.Error(fmt.Sprintf("Insufficient balance: have %.2f %s, need %.2f", balance, currency, amount))
+	}
+
+	if err := putBalance(stub, account, currency, balance-amount); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to withdraw: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Withdrew %.2f %s from %s", amount, currency, account)))
 }
 
-// getProductProvenance retrieves all provenance records for a product
-func (cc *SupplyChainChaincode) getProductProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
-	if err := shim.ValidateArgs(args, 1); err != nil {
+// createEscrow locks buyer's funds under escrow:<transactionID> until the
+// referenced shipment is confirmed delivered, so a buyer's payment can't be
+// released to the seller before the goods actually arrive.
+func (cc *SupplyChainChaincode) createEscrow(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 6); err != nil {
 		return shim.Error(err.Error())
 	}
-	
-	productID := args[0]
-	
-	iterator, err := stub.GetStateByRange("provenance:", "provenance:~")
+
+	transactionID := args[0]
+	buyer := args[1]
+	seller := args[2]
+	amount, err := strconv.ParseFloat(args[3], 64)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to get provenance records: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Invalid amount: %s", args[3]))
 	}
-	
-	results, err := shim.IteratorToArray(iterator)
+	currency := args[4]
+	shipmentID := args[5]
+
+	if _, identityID, err := callerIdentity(stub); err != nil {
+		return shim.Error(err.Error())
+	} else if identityID != buyer {
+		return shim.Error(fmt.Sprintf("Access denied: %s is not the buyer (%s) for this escrow", identityID, buyer))
+	}
+
+	existing, err := stub.GetState("escrow:" + transactionID)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Failed to check existing escrow: %s", err.Error()))
 	}
-	
-	var provenanceRecords []ProvenanceRecord
-	for _, result := range results {
-		var provenance ProvenanceRecord
-		provenanceBytes, _ := json.Marshal(result.Value)
-		json.Unmarshal(provenanceBytes, &provenance)
-		
-		if provenance.ProductID == productID {
-			provenanceRecords = append(provenanceRecords, provenance)
-		}
+	if existing != nil {
+		return shim.Error(fmt.Sprintf("Escrow already exists: %s", transactionID))
 	}
-	
-	responseBytes, err := json.Marshal(provenanceRecords)
-	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+
+	var shipment Shipment
+	if err := shim.GetStateAsJSON(stub, "shipment:"+shipmentID, &shipment); err != nil {
+		return shim.Error(fmt.Sprintf("Shipment not found: %s", shipmentID))
 	}
-	
-	return shim.Success(responseBytes)
-}
+
+	balance, err := getBalance(stub, buyer, currency)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to read balance: %s", err.Error()))
+	}
+	if balance < amount {
+		return shim.Error(fmt.Sprintf("Insufficient balance: have %.2f %s, need %.2f", balance, currency, amount))
+	}
+
+	if err := putBalance(stub, buyer, currency, balance-amount); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to lock funds: %s", err.Error()))
+	}
+
+	escrow := Escrow{
+		TransactionID: transactionID,
+		Buyer:         buyer,
+		Seller:        seller,
+		Amount:        amount,
+		Currency:      currency,
+		ShipmentID:    shipmentID,
+		Status:        "locked",
+		CreatedAt:     time.Now().Unix(),
+	}
+
+	if err := shim.PutStateAsJSON(stub, "escrow:"+transactionID, escrow); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to create escrow: %s", err.Error()))
+	}
+
+	if _, err := cc.recordTransactionInternal(stub, shipment.ProductID, shipmentID, "escrow_lock", buyer, seller, "locked", amount, currency); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	eventPayload, _ := json.Marshal(map[string]interface{}{
+		"transactionID": transactionID,
+		"buyer":         buyer,
+		"seller":        seller,
+		"amount":        amount,
+		"currency":      currency,
+		"shipmentID":    shipmentID,
+	})
+	stub.SetEvent("EscrowLocked", eventPayload)
+
+	return shim.Success([]byte(fmt.Sprintf("Escrow %s locked", transactionID)))
+}
+
+// releaseEscrow pays the locked funds to the seller, but only once the
+// referenced shipment's status has transitioned to delivered.
+func (cc *SupplyChainChaincode) releaseEscrow(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	transactionID := args[0]
+
+	var escrow Escrow
+	if err := shim.GetStateAsJSON(stub, "escrow:"+transactionID, &escrow); err != nil {
+		return shim.Error(fmt.Sprintf("Escrow not found: %s", transactionID))
+	}
+	if escrow.Status != "locked" {
+		return shim.Error(fmt.Sprintf("Escrow %s is not locked (status: %s)", transactionID, escrow.Status))
+	}
+
+	if _, identityID, err := callerIdentity(stub); err != nil {
+		return shim.Error(err.Error())
+	} else if identityID != escrow.Buyer {
+		return shim.Error(fmt.Sprintf("Access denied: %s is not the buyer (%s) of escrow %s", identityID, escrow.Buyer, transactionID))
+	}
+
+	var shipment Shipment
+	if err := shim.GetStateAsJSON(stub, "shipment:"+escrow.ShipmentID, &shipment); err != nil {
+		return shim.Error(fmt.Sprintf("Shipment not found: %s", escrow.ShipmentID))
+	}
+	if shipment.Status != "delivered" {
+		return shim.Error(fmt.Sprintf("Cannot release escrow before delivery (shipment status: %s)", shipment.Status))
+	}
+
+	balance, err := getBalance(stub, escrow.Seller, escrow.Currency)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to read balance: %s", err.Error()))
+	}
+	if err := putBalance(stub, escrow.Seller, escrow.Currency, balance+escrow.Amount); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to release funds: %s", err.Error()))
+	}
+
+	escrow.Status = "released"
+	escrow.ResolvedAt = time.Now().Unix()
+	if err := shim.PutStateAsJSON(stub, "escrow:"+transactionID, escrow); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to update escrow: %s", err.Error()))
+	}
+
+	if _, err := cc.recordTransactionInternal(stub, shipment.ProductID, escrow.ShipmentID, "escrow_release", escrow.Buyer, escrow.Seller, "released", escrow.Amount, escrow.Currency); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	eventPayload, _ := json.Marshal(map[string]interface{}{
+		"transactionID": transactionID,
+		"seller":        escrow.Seller,
+		"amount":        escrow.Amount,
+		"currency":      escrow.Currency,
+	})
+	stub.SetEvent("EscrowReleased", eventPayload)
+
+	return shim.Success([]byte(fmt.Sprintf("Escrow %s released to %s", transactionID, escrow.Seller)))
+}
+
+// refundEscrow returns the locked funds to the buyer for a dispute or
+// cancellation, instead of paying the seller.
+func (cc *SupplyChainChaincode) refundEscrow(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	transactionID := args[0]
+	reason := args[1]
+
+	// Redirecting an already-locked payment back to the buyer is a dispute
+	// resolution, not something either counterparty can trigger unilaterally;
+	// it requires an admin acting as arbitrator.
+	if _, err := requireRole(stub, roleAdmin); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	var escrow Escrow
+	if err := shim.GetStateAsJSON(stub, "escrow:"+transactionID, &escrow); err != nil {
+		return shim.Error(fmt.Sprintf("Escrow not found: %s", transactionID))
+	}
+	if escrow.Status != "locked" {
+		return shim.Error(fmt.Sprintf("Escrow %s is not locked (status: %s)", transactionID, escrow.Status))
+	}
+
+	balance, err := getBalance(stub, escrow.Buyer, escrow.Currency)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to read balance: %s", err.Error()))
+	}
+	if err := putBalance(stub, escrow.Buyer, escrow.Currency, balance+escrow.Amount); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to refund funds: %s", err.Error()))
+	}
+
+	escrow.Status = "refunded"
+	escrow.Reason = reason
+	escrow.ResolvedAt = time.Now().Unix()
+	if err := shim.PutStateAsJSON(stub, "escrow:"+transactionID, escrow); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to update escrow: %s", err.Error()))
+	}
+
+	var shipmentProductID string
+	var shipment Shipment
+	if err := shim.GetStateAsJSON(stub, "shipment:"+escrow.ShipmentID, &shipment); err == nil {
+		shipmentProductID = shipment.ProductID
+	}
+
+	if _, err := cc.recordTransactionInternal(stub, shipmentProductID, escrow.ShipmentID, "escrow_refund", escrow.Seller, escrow.Buyer, "refunded", escrow.Amount, escrow.Currency); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	eventPayload, _ := json.Marshal(map[string]interface{}{
+		"transactionID": transactionID,
+		"buyer":         escrow.Buyer,
+		"amount":        escrow.Amount,
+		"currency":      escrow.Currency,
+		"reason":        reason,
+	})
+	stub.SetEvent("EscrowRefunded", eventPayload)
+
+	return shim.Success([]byte(fmt.Sprintf("Escrow %s refunded to %s", transactionID, escrow.Buyer)))
+}
+
+// getTransaction retrieves a transaction by ID
+func (cc *SupplyChainChaincode) getTransaction(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+	
+	transactionID := args[0]
+	
+	transactionBytes, err := stub.GetState("transaction:" + transactionID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get transaction: %s", err.Error()))
+	}
+	if transactionBytes == nil {
+		return shim.Error(fmt.Sprintf("Transaction not found: %s", transactionID))
+	}
+	
+	return shim.Success(transactionBytes)
+}
+
+// getProductTransactions retrieves all transactions for a product
+func (cc *SupplyChainChaincode) getProductTransactions(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+	
+	productID := args[0]
+
+	// Range-scan the txByProduct index instead of every transaction.
+	iterator, err := stub.GetStateByPartialCompositeKey(txByProductIndex, []string{productID})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get transactions: %s", err.Error()))
+	}
+	defer iterator.Close()
+
+	var productTransactions []Transaction
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
+		}
+
+		var transaction Transaction
+		if err := shim.GetStateAsJSON(stub, "transaction:"+string(kv.Value), &transaction); err != nil {
+			continue
+		}
+		productTransactions = append(productTransactions, transaction)
+	}
+
+	responseBytes, err := json.Marshal(productTransactions)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+	
+	return shim.Success(responseBytes)
+}
+
+// recordProvenance records a provenance entry. An optional 6th argument
+// names a private data collection (e.g. "manufacturerPrivate",
+// "regulatorPrivate") to hold the evidence payload off-channel instead of
+// on the public ledger.
+func (cc *SupplyChainChaincode) recordProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 5, 6); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if _, err := requireRole(stub, roleManufacturer); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+	action := args[1]
+	actor := args[2]
+	location := args[3]
+	evidenceJSON := args[4]
+
+	var collection string
+	if len(args) > 5 {
+		collection = args[5]
+	}
+
+	// Parse evidence
+	var evidence map[string]interface{}
+	err := json.Unmarshal([]byte(evidenceJSON), &evidence)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid evidence JSON: %s", err.Error()))
+	}
+
+	return cc.recordProvenanceResponse(stub, productID, action, actor, location, evidence, collection)
+}
+
+// verifyProvenance verifies a provenance record
+func (cc *SupplyChainChaincode) verifyProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+	
+	provenanceID := args[0]
+	verifier := args[1]
+
+	if _, err := requireRole(stub, roleAuditor); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	// Get provenance record
+	var provenance ProvenanceRecord
+	err := shim.GetStateAsJSON(stub, provenanceID, &provenance)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+	}
+	
+	// Update verification status
+	provenance.Verified = true
+	if provenance.Evidence == nil {
+		provenance.Evidence = make(map[string]interface{})
+	}
+	provenance.Evidence["verifiedBy"] = verifier
+	provenance.Evidence["verifiedAt"] = time.Now().Unix()
+	
+	// Store updated provenance
+	err = shim.PutStateAsJSON(stub, provenanceID, provenance)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to verify provenance: %s", err.Error()))
+	}
+	
+	return shim.Success([]byte(fmt.Sprintf("Provenance %s verified successfully", provenanceID)))
+}
+
+// registerSigner stores the public key for an off-chain evidence signer
+// (a lab, carrier, or sensor gateway) under signer:<id>, so attachEvidence
+// and verifyEvidence can later check signatures against it.
+func (cc *SupplyChainChaincode) registerSigner(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	signerID := args[0]
+	pubkeyPEM := args[1]
+
+	if err := shim.PutStateAsString(stub, "signer:"+signerID, pubkeyPEM); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to register signer: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Signer registered: %s", signerID)))
+}
+
+// evidenceAnchorMessage is the canonical payload an evidence anchor's
+// signature covers: the artifact's hash, its location, the product it
+// concerns, and the provenance record's timestamp, so a signature cannot
+// be replayed against a different artifact or record.
+func evidenceAnchorMessage(anchor *EvidenceAnchor, productID string, timestamp int64) []byte {
+	return []byte(anchor.SHA256 + anchor.URI + productID + strconv.FormatInt(timestamp, 10))
+}
+
+// attachEvidence binds an off-chain artifact to a provenance record. The
+// anchor's signature is verified against its registered signer before it
+// is accepted, so only a key holder the chaincode already trusts can
+// attach evidence to a product's history.
+func (cc *SupplyChainChaincode) attachEvidence(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	provenanceID := args[0]
+	anchorJSON := args[1]
+
+	var provenance ProvenanceRecord
+	if err := shim.GetStateAsJSON(stub, provenanceID, &provenance); err != nil {
+		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+	}
+
+	var anchor EvidenceAnchor
+	if err := json.Unmarshal([]byte(anchorJSON), &anchor); err != nil {
+		return shim.Error(fmt.Sprintf("Invalid evidence anchor JSON: %s", err.Error()))
+	}
+
+	pubkey, err := shim.GetStateAsString(stub, "signer:"+anchor.SignerID)
+	if err != nil || pubkey == "" {
+		return shim.Error(fmt.Sprintf("Unknown signer: %s", anchor.SignerID))
+	}
+
+	signature, err := shim.DecodeSignature(anchor.Signature)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid signature encoding: %s", err.Error()))
+	}
+
+	message := evidenceAnchorMessage(&anchor, provenance.ProductID, provenance.Timestamp)
+	ok, err := shim.VerifySignature(pubkey, shim.SignatureAlgorithm(anchor.SignatureAlg), message, signature)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Signature verification failed: %s", err.Error()))
+	}
+	if !ok {
+		return shim.Error("Evidence anchor signature does not verify against the registered signer")
+	}
+
+	anchorID, err := allocateID(stub, "evidenceAnchor")
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to allocate evidence anchor ID: %s", err.Error()))
+	}
+	anchor.ID = anchorID
+	anchor.ProvenanceID = provenanceID
+	anchor.AttachedAt = time.Now().Unix()
+
+	if err := shim.PutStateAsJSON(stub, "evidenceAnchor:"+anchorID, anchor); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store evidence anchor: %s", err.Error()))
+	}
+
+	if err := indexEvidenceByProvenance(stub, provenanceID, anchorID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index evidence anchor: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(anchorID))
+}
+
+// verifyEvidence re-checks the signature of every evidence anchor attached
+// to a provenance record against its signer's currently registered key,
+// and reports per-anchor tamper status. A previously-valid anchor fails
+// here if the underlying artifact's hash was forged into the anchor, or if
+// the signer's key was rotated or revoked since attachment.
+func (cc *SupplyChainChaincode) verifyEvidence(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	provenanceID := args[0]
+
+	var provenance ProvenanceRecord
+	if err := shim.GetStateAsJSON(stub, provenanceID, &provenance); err != nil {
+		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+	}
+
+	iterator, err := stub.GetStateByPartialCompositeKey(evidenceByProvenanceIndex, []string{provenanceID})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get evidence anchors: %s", err.Error()))
+	}
+	defer iterator.Close()
+
+	type anchorStatus struct {
+		Anchor   EvidenceAnchor `json:"anchor"`
+		Valid    bool           `json:"valid"`
+		Tampered bool           `json:"tampered"`
+	}
+
+	var results []anchorStatus
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to process results: %s", err.Error()))
+		}
+
+		var anchor EvidenceAnchor
+		if err := shim.GetStateAsJSON(stub, "evidenceAnchor:"+string(kv.Value), &anchor); err != nil {
+			continue
+		}
+
+		status := anchorStatus{Anchor: anchor}
+
+		pubkey, err := shim.GetStateAsString(stub, "signer:"+anchor.SignerID)
+		if err != nil || pubkey == "" {
+			status.Tampered = true
+			results = append(results, status)
+			continue
+		}
+
+		signature, err := shim.DecodeSignature(anchor.Signature)
+		if err != nil {
+			status.Tampered = true
+			results = append(results, status)
+			continue
+		}
+
+		message := evidenceAnchorMessage(&anchor, provenance.ProductID, provenance.Timestamp)
+		ok, err := shim.VerifySignature(pubkey, shim.SignatureAlgorithm(anchor.SignatureAlg), message, signature)
+		status.Valid = err == nil && ok
+		status.Tampered = !status.Valid
+		results = append(results, status)
+	}
+
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal results: %s", err.Error()))
+	}
+
+	return shim.Success(resultsJSON)
+}
+
+// issueCertificate issues a new active certificate attesting to some fact
+// about a product or actor (organic, fair-trade, lab-tested, etc.) on
+// behalf of the calling org. The issuing org is the caller's MSP ID, so a
+// certificate can later be checked against whichever org is attaching it
+// to a provenance record.
+func (cc *SupplyChainChaincode) issueCertificate(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 4); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+	actor := args[1]
+	industry := args[2]
+	imageURL := args[3]
+
+	mspID, _, err := callerIdentity(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to resolve caller identity: %s", err.Error()))
+	}
+
+	certificateID, err := allocateID(stub, "certificate")
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to allocate certificate ID: %s", err.Error()))
+	}
+
+	now := time.Now().Unix()
+	certificate := Certificate{
+		ID:        certificateID,
+		Issuer:    mspID,
+		ProductID: productID,
+		Actor:     actor,
+		Industry:  industry,
+		ImageURL:  imageURL,
+		Status:    "active",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := shim.PutStateAsJSON(stub, "certificate:"+certificateID, certificate); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store certificate: %s", err.Error()))
+	}
+
+	if err := indexCertificateByProduct(stub, productID, certificateID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index certificate: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(certificateID))
+}
+
+// revokeCertificate marks an existing certificate "revoked", so it can no
+// longer back new provenance records even though it remains on the ledger
+// for audit purposes. Only the issuing org may revoke its own certificate.
+func (cc *SupplyChainChaincode) revokeCertificate(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	certificateID := args[0]
+
+	var certificate Certificate
+	if err := shim.GetStateAsJSON(stub, "certificate:"+certificateID, &certificate); err != nil {
+		return shim.Error(fmt.Sprintf("Certificate not found: %s", certificateID))
+	}
+
+	mspID, _, err := callerIdentity(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to resolve caller identity: %s", err.Error()))
+	}
+	if mspID != certificate.Issuer {
+		return shim.Error(fmt.Sprintf("Access denied: %s did not issue certificate %s", mspID, certificateID))
+	}
+
+	certificate.Status = "revoked"
+	certificate.UpdatedAt = time.Now().Unix()
+
+	if err := shim.PutStateAsJSON(stub, "certificate:"+certificateID, certificate); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to revoke certificate: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Certificate revoked: %s", certificateID)))
+}
+
+// getCertificatesForProduct returns every certificate issued against a
+// product, active or revoked, via the certificateByProduct index rather
+// than scanning every certificate ever issued.
+func (cc *SupplyChainChaincode) getCertificatesForProduct(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+
+	iterator, err := stub.GetStateByPartialCompositeKey(certificateByProductIndex, []string{productID})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to query certificate index: %s", err.Error()))
+	}
+	defer iterator.Close()
+
+	var certificates []Certificate
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to iterate certificate index: %s", err.Error()))
+		}
+
+		var certificate Certificate
+		if err := shim.GetStateAsJSON(stub, "certificate:"+string(kv.Value), &certificate); err != nil {
+			continue
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	certificatesJSON, err := json.Marshal(certificates)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal certificates: %s", err.Error()))
+	}
+
+	return shim.Success(certificatesJSON)
+}
+
+// attachCertificateToProvenance records a certificate ID against an
+// existing provenance record's evidence, validating it the same way
+// recordProvenanceResponse validates evidence["certificateIDs"] at write
+// time: the certificate must be active and issued by the calling org.
+func (cc *SupplyChainChaincode) attachCertificateToProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	provenanceID := args[0]
+	certificateID := args[1]
+
+	var provenance ProvenanceRecord
+	if err := shim.GetStateAsJSON(stub, provenanceID, &provenance); err != nil {
+		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+	}
+
+	if err := validateCertificateForAttachment(stub, certificateID); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if provenance.Evidence == nil {
+		provenance.Evidence = make(map[string]interface{})
+	}
+
+	existing, _ := provenance.Evidence["certificateIDs"].([]interface{})
+	provenance.Evidence["certificateIDs"] = append(existing, certificateID)
+
+	if err := shim.PutStateAsJSON(stub, provenanceID, provenance); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to attach certificate: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Certificate %s attached to provenance %s", certificateID, provenanceID)))
+}
+
+// validateCertificateForAttachment rejects a certificate that doesn't
+// exist, isn't active, or wasn't issued by the calling org's MSP ID — the
+// same check recordProvenanceResponse runs against every certificate ID
+// referenced in evidence["certificateIDs"] before a provenance write is
+// allowed to claim it.
+func validateCertificateForAttachment(stub shim.ChaincodeStubInterface, certificateID string) error {
+	var certificate Certificate
+	if err := shim.GetStateAsJSON(stub, "certificate:"+certificateID, &certificate); err != nil {
+		return fmt.Errorf("certificate not found: %s", certificateID)
+	}
+
+	if certificate.Status != "active" {
+		return fmt.Errorf("certificate %s is not active (status: %s)", certificateID, certificate.Status)
+	}
+
+	mspID, _, err := callerIdentity(stub)
+	if err != nil {
+		return fmt.Errorf("failed to resolve caller identity: %w", err)
+	}
+	if mspID != certificate.Issuer {
+		return fmt.Errorf("access denied: %s did not issue certificate %s", mspID, certificateID)
+	}
+
+	return nil
+}
+
+// provenanceIDsForProduct range-scans the provenanceByProduct index and
+// returns productID's provenance record keys in chronological order,
+// instead of every caller having to walk the index itself.
+func provenanceIDsForProduct(stub shim.ChaincodeStubInterface, productID string) ([]string, error) {
+	iterator, err := stub.GetStateByPartialCompositeKey(provenanceByProductIndex, []string{productID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provenance index: %w", err)
+	}
+	defer iterator.Close()
+
+	var ids []string
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate provenance index: %w", err)
+		}
+		ids = append(ids, string(kv.Value))
+	}
+
+	return ids, nil
+}
+
+// provenanceRecordsForProduct resolves productID's provenance record keys
+// to their current ProvenanceRecord values, in chronological order.
+func provenanceRecordsForProduct(stub shim.ChaincodeStubInterface, productID string) ([]ProvenanceRecord, error) {
+	ids, err := provenanceIDsForProduct(stub, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []ProvenanceRecord
+	for _, id := range ids {
+		var record ProvenanceRecord
+		if err := shim.GetStateAsJSON(stub, id, &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// getProductProvenance retrieves all provenance records for a product
+func (cc *SupplyChainChaincode) getProductProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+
+	provenanceRecords, err := provenanceRecordsForProduct(stub, productID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get provenance records: %s", err.Error()))
+	}
+
+	responseBytes, err := json.Marshal(provenanceRecords)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// getProvenanceBetween retrieves productID's provenance chain restricted to
+// records timestamped between fromTs and toTs (Unix seconds, inclusive), so
+// regulators can pull a time-bounded window instead of the whole history.
+func (cc *SupplyChainChaincode) getProvenanceBetween(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 3); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+	fromTs, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid fromTs: %s", args[1]))
+	}
+	toTs, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid toTs: %s", args[2]))
+	}
+
+	allRecords, err := provenanceRecordsForProduct(stub, productID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get provenance records: %s", err.Error()))
+	}
+
+	provenanceRecords := make([]ProvenanceRecord, 0, len(allRecords))
+	for _, record := range allRecords {
+		if record.Timestamp >= fromTs && record.Timestamp <= toTs {
+			provenanceRecords = append(provenanceRecords, record)
+		}
+	}
+
+	responseBytes, err := json.Marshal(provenanceRecords)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// ProvenanceQueryResponse carries a page of rich-query results alongside
+// the bookmark a caller passes back in as the next page's starting point.
+type ProvenanceQueryResponse struct {
+	Records  []ProvenanceRecord `json:"records"`
+	Bookmark string             `json:"bookmark"`
+}
+
+// queryProvenance runs a CouchDB Mango selector (args[0]), unchanged, via
+// stub.GetQueryResultWithPagination, paging with optional args[1]
+// (pageSize, default 20) and args[2] (bookmark). Only a selector that
+// names "docType":"provenance" is limited to provenance records.
+// Requires CouchDB as the state database; selectors like
+// {"docType":"provenance","productID":"X","action":"SHIPPED"} are served by
+// the composite indexes shipped in META-INF/statedb/couchdb/indexes/
+// instead of falling back to a full data scan.
+func (cc *SupplyChainChaincode) queryProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 1, 3); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	selector := args[0]
+
+	var pageSize int32 = 20
+	if len(args) > 1 && args[1] != "" {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Invalid pageSize: %s", args[1]))
+		}
+		pageSize = int32(parsed)
+	}
+
+	bookmark := ""
+	if len(args) > 2 {
+		bookmark = args[2]
+	}
+
+	iterator, metadata, err := stub.GetQueryResultWithPagination(selector, pageSize, bookmark)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to run provenance query: %s", err.Error()))
+	}
+	defer iterator.Close()
+
+	records := make([]ProvenanceRecord, 0)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to iterate query results: %s", err.Error()))
+		}
+
+		var record ProvenanceRecord
+		if err := json.Unmarshal(kv.Value, &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	responseBytes, err := json.Marshal(ProvenanceQueryResponse{
+		Records:  records,
+		Bookmark: metadata.Bookmark,
+	})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// CrossChainLink records the remote call invokeExternalProvenance made to
+// anchor a local provenance event against an upstream product tracked by a
+// raw-material chaincode on another channel — its own consortium, its own
+// ledger. TxID is the (shared) transaction ID the invocation ran under, and
+// PayloadHash commits to the remote response so verifyCrossChainLink can
+// later detect drift without re-trusting the remote chaincode's response
+// at face value.
+type CrossChainLink struct {
+	ChannelID       string `json:"channelID"`
+	ChaincodeName   string `json:"chaincodeName"`
+	LinkedProductID string `json:"linkedProductID"`
+	TxID            string `json:"txID"`
+	PayloadHash     string `json:"payloadHash"`
+}
+
+// invokeExternalProvenance calls getProductProvenance on a raw-material
+// chaincode running on another channel and records the result as a local
+// provenance event, so a finished-goods product's history can reference an
+// upstream tier that this chaincode has no direct visibility into. The
+// remote response is committed to (not copied wholesale) via a SHA-256
+// hash under Evidence["crossChainLink"], matching the pattern
+// recordProvenanceResponse already uses to commit to off-chain evidence.
+func (cc *SupplyChainChaincode) invokeExternalProvenance(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 4); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	channelID := args[0]
+	chaincodeName := args[1]
+	productID := args[2]
+	linkedProductID := args[3]
+
+	resp := stub.InvokeChaincode(chaincodeName, [][]byte{[]byte("getProductProvenance"), []byte(linkedProductID)}, channelID)
+	if resp.Status != 200 {
+		return shim.Error(fmt.Sprintf("Failed to invoke %s on channel %s for product %s: %s", chaincodeName, channelID, linkedProductID, resp.Message))
+	}
+
+	hash := sha256.Sum256(resp.Payload)
+
+	mspID, identityID, err := callerIdentity(stub)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to resolve caller identity: %s", err.Error()))
+	}
+	actor := fmt.Sprintf("%s:%s", mspID, identityID)
+
+	evidence := map[string]interface{}{
+		"crossChainLink": CrossChainLink{
+			ChannelID:       channelID,
+			ChaincodeName:   chaincodeName,
+			LinkedProductID: linkedProductID,
+			TxID:            stub.GetTxID(),
+			PayloadHash:     hex.EncodeToString(hash[:]),
+		},
+	}
+
+	return cc.recordProvenanceResponse(stub, productID, "CROSS_CHAIN_LINK", actor, channelID, evidence, "")
+}
+
+// verifyCrossChainLink re-invokes the remote chaincode named in
+// provenanceID's crossChainLink evidence and confirms the linked product's
+// provenance still exists and still hashes to the committed PayloadHash, so
+// a tampered or since-altered upstream record is detected rather than
+// trusted on the strength of the original invocation alone.
+func (cc *SupplyChainChaincode) verifyCrossChainLink(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	provenanceID := args[0]
+
+	var provenance ProvenanceRecord
+	if err := shim.GetStateAsJSON(stub, provenanceID, &provenance); err != nil {
+		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+	}
+
+	linkRaw, ok := provenance.Evidence["crossChainLink"]
+	if !ok {
+		return shim.Error(fmt.Sprintf("Provenance record %s has no cross-chain link", provenanceID))
+	}
+
+	linkBytes, err := json.Marshal(linkRaw)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal cross-chain link: %s", err.Error()))
+	}
+
+	var link CrossChainLink
+	if err := json.Unmarshal(linkBytes, &link); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse cross-chain link: %s", err.Error()))
+	}
+
+	resp := stub.InvokeChaincode(link.ChaincodeName, [][]byte{[]byte("getProductProvenance"), []byte(link.LinkedProductID)}, link.ChannelID)
+
+	result := map[string]interface{}{
+		"provenanceID":    provenanceID,
+		"channelID":       link.ChannelID,
+		"chaincodeName":   link.ChaincodeName,
+		"linkedProductID": link.LinkedProductID,
+	}
+
+	if resp.Status != 200 {
+		result["match"] = false
+		result["reason"] = fmt.Sprintf("remote invocation failed: %s", resp.Message)
+	} else {
+		hash := sha256.Sum256(resp.Payload)
+		result["match"] = hex.EncodeToString(hash[:]) == link.PayloadHash
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal result: %s", err.Error()))
+	}
+
+	return shim.Success(resultBytes)
+}
 
 // traceProduct provides complete traceability for a product
 func (cc *SupplyChainChaincode) traceProduct(stub shim.ChaincodeStubInterface, args []string) shim.Response {
 	if err := shim.ValidateArgs(args, 1); err != nil {
 		return shim.Error(err.Error())
 	}
-	
+	
+	productID := args[0]
+	
+	// Get product
+	var product Product
+	err := shim.GetStateAsJSON(stub, "product:"+productID, &product)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Product not found: %s", productID))
+	}
+	
+	// Get provenance records
+	provenanceResponse := cc.getProductProvenance(stub, []string{productID})
+	if provenanceResponse.Status != 200 {
+		return provenanceResponse
+	}
+	
+	var provenanceRecords []ProvenanceRecord
+	json.Unmarshal(provenanceResponse.Payload, &provenanceRecords)
+	
+	// Get transactions
+	transactionsResponse := cc.getProductTransactions(stub, []string{productID})
+	var transactions []Transaction
+	if transactionsResponse.Status == 200 {
+		json.Unmarshal(transactionsResponse.Payload, &transactions)
+	}
+	
+	// Get shipments via the shipmentByProduct index instead of scanning
+	// every shipment in the ledger.
+	shipmentIterator, err := stub.GetStateByPartialCompositeKey(shipmentByProductIndex, []string{productID})
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get shipments: %s", err.Error()))
+	}
+	defer shipmentIterator.Close()
+
+	var shipments []Shipment
+	for shipmentIterator.HasNext() {
+		kv, err := shipmentIterator.Next()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to process shipment results: %s", err.Error()))
+		}
+
+		var shipment Shipment
+		if err := shim.GetStateAsJSON(stub, "shipment:"+string(kv.Value), &shipment); err != nil {
+			continue
+		}
+		shipments = append(shipments, shipment)
+	}
+	
+	// Get bill of materials, expanded to its full recursive DAG
+	billOfMaterials, err := cc.buildUpstreamNode(stub, productID, 0, "", "", defaultTraceDepth, 0, make(map[string]bool))
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to build bill of materials: %s", err.Error()))
+	}
+
+	// Create trace response
+	trace := map[string]interface{}{
+		"product":         product,
+		"provenance":      provenanceRecords,
+		"transactions":    transactions,
+		"shipments":       shipments,
+		"billOfMaterials": billOfMaterials,
+		"generatedAt":     time.Now().Unix(),
+	}
+
+	// Merge in the private view if the caller's org is a member of the
+	// manufacturer's collection. A caller without membership simply gets
+	// no private data back from the peer, so this is best-effort: a
+	// missing or unreadable record is not treated as an error.
+	var privateData ProductPrivateData
+	if err := shim.GetPrivateStateAsJSON(stub, manufacturerPrivateCollection, "product:"+productID, &privateData); err == nil {
+		trace["privateData"] = privateData
+	}
+
+	responseBytes, err := json.Marshal(trace)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal trace response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// addComponent adds a component link to a product's bill of materials,
+// recording the quantity and unit of the child product or raw material
+// consumed and, optionally, which batch it was drawn from.
+func (cc *SupplyChainChaincode) addComponent(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 4, 5); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	parentID := args[0]
+	childID := args[1]
+	quantity, err := strconv.ParseFloat(args[2], 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid quantity: %s", args[2]))
+	}
+	unit := args[3]
+
+	var batchID string
+	if len(args) > 4 {
+		batchID = args[4]
+	}
+
+	// Verify parent product exists
+	var parent Product
+	if err := shim.GetStateAsJSON(stub, "product:"+parentID, &parent); err != nil {
+		return shim.Error(fmt.Sprintf("Product not found: %s", parentID))
+	}
+
+	link := ComponentLink{
+		ParentID: parentID,
+		ChildID:  childID,
+		Quantity: quantity,
+		Unit:     unit,
+		BatchID:  batchID,
+		AddedAt:  time.Now().Unix(),
+		Metadata: make(map[string]interface{}),
+	}
+
+	err = shim.PutStateAsJSON(stub, "bom:"+parentID+":"+childID, link)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store component link: %s", err.Error()))
+	}
+
+	// Record provenance
+	cc.recordProvenanceInternal(stub, parentID, "ADD_COMPONENT", parentID, "", map[string]interface{}{
+		"childID":  childID,
+		"quantity": quantity,
+		"unit":     unit,
+		"batchID":  batchID,
+	})
+
+	// Emit event
+	eventPayload, _ := json.Marshal(map[string]interface{}{
+		"action":   "component_added",
+		"parentID": parentID,
+		"childID":  childID,
+		"quantity": quantity,
+		"unit":     unit,
+	})
+	stub.SetEvent("ComponentAdded", eventPayload)
+
+	return shim.Success([]byte(fmt.Sprintf("Component %s added to %s", childID, parentID)))
+}
+
+// removeComponent removes a component link from a product's bill of
+// materials.
+func (cc *SupplyChainChaincode) removeComponent(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	parentID := args[0]
+	childID := args[1]
+	key := "bom:" + parentID + ":" + childID
+
+	existing, err := stub.GetState(key)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to check component link: %s", err.Error()))
+	}
+	if existing == nil {
+		return shim.Error(fmt.Sprintf("Component link not found: %s -> %s", parentID, childID))
+	}
+
+	if err := stub.DelState(key); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to remove component link: %s", err.Error()))
+	}
+
+	// Record provenance
+	cc.recordProvenanceInternal(stub, parentID, "REMOVE_COMPONENT", parentID, "", map[string]interface{}{
+		"childID": childID,
+	})
+
+	return shim.Success([]byte(fmt.Sprintf("Component %s removed from %s", childID, parentID)))
+}
+
+// getBOM retrieves a product's direct bill of materials, one level deep.
+// Use traceUpstream to expand the full recursive tree.
+func (cc *SupplyChainChaincode) getBOM(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	parentID := args[0]
+
+	links, err := cc.directComponents(stub, parentID)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	responseBytes, err := json.Marshal(links)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal BOM response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// directComponents returns the immediate component links of parentID by
+// range-scanning its bom:<parentID>: keys.
+func (cc *SupplyChainChaincode) directComponents(stub shim.ChaincodeStubInterface, parentID string) ([]ComponentLink, error) {
+	iterator, err := stub.GetStateByRange("bom:"+parentID+":", "bom:"+parentID+":~")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get components for %s: %w", parentID, err)
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process component results: %w", err)
+	}
+
+	var links []ComponentLink
+	for _, result := range results {
+		var link ComponentLink
+		linkBytes, _ := json.Marshal(result.Value)
+		json.Unmarshal(linkBytes, &link)
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// directConsumers returns the component links whose ChildID is childID —
+// every product that directly consumes it — by scanning the full bom:
+// range, since links are keyed by parent rather than child.
+func (cc *SupplyChainChaincode) directConsumers(stub shim.ChaincodeStubInterface, childID string) ([]ComponentLink, error) {
+	iterator, err := stub.GetStateByRange("bom:", "bom:~")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan component links: %w", err)
+	}
+
+	results, err := shim.IteratorToArray(iterator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process component link results: %w", err)
+	}
+
+	var links []ComponentLink
+	for _, result := range results {
+		var link ComponentLink
+		linkBytes, _ := json.Marshal(result.Value)
+		json.Unmarshal(linkBytes, &link)
+
+		if link.ChildID == childID {
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// traceUpstream recursively expands productID's bill of materials into a
+// BOMNode tree: every ingredient, and every ingredient's ingredients, down
+// to an optional max depth (args[1], defaulting to defaultTraceDepth).
+func (cc *SupplyChainChaincode) traceUpstream(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 1, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+	maxDepth := defaultTraceDepth
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Invalid max depth: %s", args[1]))
+		}
+		maxDepth = parsed
+	}
+
+	node, err := cc.buildUpstreamNode(stub, productID, 0, "", "", maxDepth, 0, make(map[string]bool))
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	responseBytes, err := json.Marshal(node)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal upstream trace: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// buildUpstreamNode recursively expands productID's bill of materials,
+// turning each component link into a nested BOMNode down to maxDepth
+// levels. visited guards against a cyclic BOM sending this into infinite
+// recursion: a product already on the current path is reported as a
+// truncated leaf instead of expanded again.
+func (cc *SupplyChainChaincode) buildUpstreamNode(stub shim.ChaincodeStubInterface, productID string, quantity float64, unit, batchID string, maxDepth, depth int, visited map[string]bool) (*BOMNode, error) {
+	node := &BOMNode{
+		ProductID: productID,
+		Quantity:  quantity,
+		Unit:      unit,
+		BatchID:   batchID,
+	}
+
+	if visited[productID] || depth >= maxDepth {
+		node.Truncated = true
+		return node, nil
+	}
+
+	visited[productID] = true
+	defer delete(visited, productID)
+
+	links, err := cc.directComponents(stub, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range links {
+		child, err := cc.buildUpstreamNode(stub, link.ChildID, link.Quantity, link.Unit, link.BatchID, maxDepth, depth+1, visited)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+// IngredientProvenanceNode is one node of a traverseIngredients tree: an
+// ingredient's own provenance chain, alongside the ingredients consumed to
+// produce it in turn.
+type IngredientProvenanceNode struct {
+	ProductID  string                      `json:"productID"`
+	Quantity   float64                     `json:"quantity,omitempty"`
+	Unit       string                      `json:"unit,omitempty"`
+	BatchID    string                      `json:"batchID,omitempty"`
+	Provenance []ProvenanceRecord          `json:"provenance"`
+	Children   []*IngredientProvenanceNode `json:"children,omitempty"`
+	Truncated  bool                        `json:"truncated,omitempty"`
+}
+
+// traverseIngredients recursively follows productID's bill of materials
+// down to an optional max depth (args[1], defaulting to defaultTraceDepth),
+// attaching each ingredient's own provenance chain so a finished product's
+// lineage can be traced back through the raw materials that went into it.
+func (cc *SupplyChainChaincode) traverseIngredients(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 1, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+	maxDepth := defaultTraceDepth
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Invalid max depth: %s", args[1]))
+		}
+		maxDepth = parsed
+	}
+
+	node, err := cc.buildIngredientProvenanceNode(stub, productID, 0, "", "", maxDepth, 0, make(map[string]bool))
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	responseBytes, err := json.Marshal(node)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal ingredient trace: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// buildIngredientProvenanceNode mirrors buildUpstreamNode's recursive BOM
+// walk, but also attaches each ingredient's own provenance chain so the
+// result ties a finished good's lineage to the raw materials it was built
+// from, not just their quantities.
+func (cc *SupplyChainChaincode) buildIngredientProvenanceNode(stub shim.ChaincodeStubInterface, productID string, quantity float64, unit, batchID string, maxDepth, depth int, visited map[string]bool) (*IngredientProvenanceNode, error) {
+	provenance, err := provenanceRecordsForProduct(stub, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &IngredientProvenanceNode{
+		ProductID:  productID,
+		Quantity:   quantity,
+		Unit:       unit,
+		BatchID:    batchID,
+		Provenance: provenance,
+	}
+
+	if visited[productID] || depth >= maxDepth {
+		node.Truncated = true
+		return node, nil
+	}
+
+	visited[productID] = true
+	defer delete(visited, productID)
+
+	links, err := cc.directComponents(stub, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range links {
+		child, err := cc.buildIngredientProvenanceNode(stub, link.ChildID, link.Quantity, link.Unit, link.BatchID, maxDepth, depth+1, visited)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+// traceDownstream recursively walks the consumers of productID — the
+// products whose bill of materials includes it, and theirs in turn — down
+// to an optional max depth (args[1], defaulting to defaultTraceDepth).
+func (cc *SupplyChainChaincode) traceDownstream(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 1, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+	maxDepth := defaultTraceDepth
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Invalid max depth: %s", args[1]))
+		}
+		maxDepth = parsed
+	}
+
+	node, err := cc.buildDownstreamNode(stub, productID, 0, "", "", maxDepth, 0, make(map[string]bool))
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	responseBytes, err := json.Marshal(node)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal downstream trace: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// buildDownstreamNode recursively walks the consumers of productID,
+// mirroring buildUpstreamNode's depth and cycle-detection bounds but in
+// the opposite graph direction.
+func (cc *SupplyChainChaincode) buildDownstreamNode(stub shim.ChaincodeStubInterface, productID string, quantity float64, unit, batchID string, maxDepth, depth int, visited map[string]bool) (*BOMNode, error) {
+	node := &BOMNode{
+		ProductID: productID,
+		Quantity:  quantity,
+		Unit:      unit,
+		BatchID:   batchID,
+	}
+
+	if visited[productID] || depth >= maxDepth {
+		node.Truncated = true
+		return node, nil
+	}
+
+	visited[productID] = true
+	defer delete(visited, productID)
+
+	links, err := cc.directConsumers(stub, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range links {
+		child, err := cc.buildDownstreamNode(stub, link.ParentID, link.Quantity, link.Unit, link.BatchID, maxDepth, depth+1, visited)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+// getProductsContainingComponent returns every product whose transitive
+// bill of materials contains componentID, for recall workflows: given a
+// contaminated raw material, this finds every finished good downstream of
+// it regardless of how many assembly levels separate them.
+func (cc *SupplyChainChaincode) getProductsContainingComponent(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	componentID := args[0]
+
+	visited := make(map[string]bool)
+	containers := make(map[string]bool)
+	if err := cc.collectConsumers(stub, componentID, visited, containers); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productIDs := make([]string, 0, len(containers))
+	for productID := range containers {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+
+	responseBytes, err := json.Marshal(productIDs)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// collectConsumers transitively walks componentID's consumers, recording
+// every product reached in containers. visited guards against revisiting a
+// product already expanded on this path, the same cycle-detection approach
+// buildDownstreamNode uses.
+func (cc *SupplyChainChaincode) collectConsumers(stub shim.ChaincodeStubInterface, componentID string, visited, containers map[string]bool) error {
+	if visited[componentID] {
+		return nil
+	}
+	visited[componentID] = true
+
+	links, err := cc.directConsumers(stub, componentID)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		containers[link.ParentID] = true
+		if err := cc.collectConsumers(stub, link.ParentID, visited, containers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createProductPrivate creates a product's public record exactly like
+// createProduct, plus a ProductPrivateData record (cost, supplier, buyer)
+// written to the manufacturerPrivateCollection so only the manufacturer's
+// org can read it.
+func (cc *SupplyChainChaincode) createProductPrivate(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgsRange(args, 7, 8); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if _, err := requireRole(stub, roleManufacturer); err != nil {
+		return shim.Error(err.Error())
+	}
+
 	productID := args[0]
-	
-	// Get product
-	var product Product
-	err := shim.GetStateAsJSON(stub, "product:"+productID, &product)
+	name := args[1]
+	description := args[2]
+	sku := args[3]
+	manufacturer := args[4]
+	unitCost, err := strconv.ParseFloat(args[5], 64)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Product not found: %s", productID))
+		return shim.Error(fmt.Sprintf("Invalid unit cost: %s", args[5]))
 	}
-	
-	// Get provenance records
-	provenanceResponse := cc.getProductProvenance(stub, []string{productID})
-	if provenanceResponse.Status != 200 {
-		return provenanceResponse
+	currency := args[6]
+
+	var supplierID string
+	if len(args) > 7 {
+		supplierID = args[7]
 	}
-	
-	var provenanceRecords []ProvenanceRecord
-	json.Unmarshal(provenanceResponse.Payload, &provenanceRecords)
-	
-	// Get transactions
-	transactionsResponse := cc.getProductTransactions(stub, []string{productID})
-	var transactions []Transaction
-	if transactionsResponse.Status == 200 {
-		json.Unmarshal(transactionsResponse.Payload, &transactions)
+
+	if productID == "" {
+		allocated, err := allocateID(stub, "product")
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to allocate product ID: %s", err.Error()))
+		}
+		productID = allocated
 	}
-	
-	// Get shipments
-	iterator, err := stub.GetStateByRange("shipment:", "shipment:~")
+
+	existing, err := stub.GetState("product:" + productID)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to get shipments: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Failed to check existing product: %s", err.Error()))
 	}
-	
-	results, err := shim.IteratorToArray(iterator)
-	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to process shipment results: %s", err.Error()))
+	if existing != nil {
+		return shim.Error(fmt.Sprintf("Product already exists: %s", productID))
 	}
-	
-	var shipments []Shipment
-	for _, result := range results {
-		var shipment Shipment
-		shipmentBytes, _ := json.Marshal(result.Value)
-		json.Unmarshal(shipmentBytes, &shipment)
-		
-		if shipment.ProductID == productID {
-			shipments = append(shipments, shipment)
-		}
+
+	product := Product{
+		ID:           productID,
+		Name:         name,
+		Description:  description,
+		SKU:          sku,
+		Manufacturer: manufacturer,
+		CreatedAt:    time.Now().Unix(),
+		Status:       "created",
+		Metadata:     make(map[string]interface{}),
 	}
-	
-	// Create trace response
-	trace := map[string]interface{}{
-		"product":     product,
-		"provenance":  provenanceRecords,
-		"transactions": transactions,
-		"shipments":   shipments,
-		"generatedAt": time.Now().Unix(),
+
+	if err := shim.PutStateAsJSON(stub, "product:"+productID, product); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store product: %s", err.Error()))
 	}
-	
-	responseBytes, err := json.Marshal(trace)
+
+	if err := indexProductByCategory(stub, product.Category, productID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index product: %s", err.Error()))
+	}
+
+	private := ProductPrivateData{
+		ProductID:  productID,
+		UnitCost:   unitCost,
+		Currency:   currency,
+		SupplierID: supplierID,
+		Metadata:   make(map[string]interface{}),
+	}
+
+	if err := shim.PutPrivateStateAsJSON(stub, manufacturerPrivateCollection, "product:"+productID, private); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store private product data: %s", err.Error()))
+	}
+
+	cc.recordProvenanceInternal(stub, productID, "CREATE", manufacturer, "factory", map[string]interface{}{
+		"sku":  sku,
+		"name": name,
+	})
+
+	eventPayload, _ := json.Marshal(map[string]interface{}{
+		"action":       "product_created",
+		"productID":    productID,
+		"name":         name,
+		"manufacturer": manufacturer,
+	})
+	stub.SetEvent("ProductCreated", eventPayload)
+
+	return shim.Success([]byte(fmt.Sprintf("Product %s created successfully", productID)))
+}
+
+// getProductPrivate retrieves a product's ProductPrivateData from the
+// manufacturerPrivateCollection. Callers outside the collection get
+// whatever the peer returns for an unreadable key, typically an error.
+func (cc *SupplyChainChaincode) getProductPrivate(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+
+	var private ProductPrivateData
+	if err := shim.GetPrivateStateAsJSON(stub, manufacturerPrivateCollection, "product:"+productID, &private); err != nil {
+		return shim.Error(fmt.Sprintf("Private product data not found: %s", productID))
+	}
+
+	responseBytes, err := json.Marshal(private)
 	if err != nil {
-		return shim.Error(fmt.Sprintf("Failed to marshal trace response: %s", err.Error()))
+		return shim.Error(fmt.Sprintf("Failed to marshal private product data: %s", err.Error()))
 	}
-	
+
 	return shim.Success(responseBytes)
 }
 
+// sharePrivateAttribute writes a single attribute into a named private
+// data collection and commits a SHA-256 hash of its value to the public
+// ledger under privatehash:<collection>:<key>:<attribute>, so organizations
+// outside the collection can verify a value they are later shown
+// off-channel without ever seeing it on the public ledger.
+func (cc *SupplyChainChaincode) sharePrivateAttribute(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 4); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	collection := args[0]
+	key := args[1]
+	attribute := args[2]
+	value := args[3]
+
+	if err := stub.PutPrivateData(collection, key+":"+attribute, []byte(value)); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store private attribute: %s", err.Error()))
+	}
+
+	hash := sha256.Sum256([]byte(value))
+	hashKey := fmt.Sprintf("privatehash:%s:%s:%s", collection, key, attribute)
+	if err := shim.PutStateAsString(stub, hashKey, hex.EncodeToString(hash[:])); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to store attribute hash: %s", err.Error()))
+	}
+
+	return shim.Success([]byte(fmt.Sprintf("Attribute %s shared for %s in collection %s", attribute, key, collection)))
+}
+
 // Helper function to record provenance internally
 func (cc *SupplyChainChaincode) recordProvenanceInternal(stub shim.ChaincodeStubInterface, productID, action, actor, location string, evidence map[string]interface{}) {
-	provenanceID := fmt.Sprintf("provenance:%s:%d:%s", productID, time.Now().UnixNano(), stub.GetTxID())
-	
+	seq, err := allocateID(stub, "provenance")
+	if err != nil {
+		seq = fmt.Sprintf("provenance-%d", time.Now().UnixNano())
+	}
+	provenanceID := fmt.Sprintf("provenance:%s:%s:%s", productID, seq, stub.GetTxID())
+
 	provenance := ProvenanceRecord{
+		DocType:   provenanceDocType,
 		ID:        provenanceID,
 		ProductID: productID,
 		Action:    action,
@@ -782,36 +3143,288 @@ func (cc *SupplyChainChaincode) recordProvenanceInternal(stub shim.ChaincodeStub
 	}
 	
 	shim.PutStateAsJSON(stub, provenanceID, provenance)
+	indexProvenanceByProduct(stub, productID, provenance.Timestamp, provenanceID)
 }
 
-// Helper function to record provenance and return response
-func (cc *SupplyChainChaincode) recordProvenanceResponse(stub shim.ChaincodeStubInterface, productID, action, actor, location string, evidence map[string]interface{}) shim.Response {
+// Helper function to record provenance and return response. When
+// collection is non-empty, the caller's evidence is written to that named
+// private data collection instead of the public ledger, and the on-chain
+// ProvenanceRecord carries only a SHA-256 commitment to it — so lab
+// results, supplier prices, and GPS coordinates stay off-channel while the
+// record is still independently verifiable via verifyPrivateEvidence.
+func (cc *SupplyChainChaincode) recordProvenanceResponse(stub shim.ChaincodeStubInterface, productID, action, actor, location string, evidence map[string]interface{}, collection string) shim.Response {
+	if certificateIDs, ok := evidence["certificateIDs"].([]interface{}); ok {
+		for _, raw := range certificateIDs {
+			certificateID, ok := raw.(string)
+			if !ok {
+				return shim.Error("evidence.certificateIDs must be a list of certificate ID strings")
+			}
+			if err := validateCertificateForAttachment(stub, certificateID); err != nil {
+				return shim.Error(err.Error())
+			}
+		}
+	}
+
 	provenanceID := fmt.Sprintf("provenance:%s:%d:%s", productID, time.Now().UnixNano(), stub.GetTxID())
-	
+
+	publicEvidence := evidence
+	if collection != "" {
+		evidenceBytes, err := json.Marshal(evidence)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to marshal evidence: %s", err.Error()))
+		}
+
+		if err := stub.PutPrivateData(collection, provenanceID, evidenceBytes); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to store private evidence: %s", err.Error()))
+		}
+
+		hash := sha256.Sum256(evidenceBytes)
+		publicEvidence = map[string]interface{}{
+			"evidenceHash":      hex.EncodeToString(hash[:]),
+			"privateCollection": collection,
+		}
+	}
+
 	provenance := ProvenanceRecord{
+		DocType:   provenanceDocType,
 		ID:        provenanceID,
 		ProductID: productID,
 		Action:    action,
 		Actor:     actor,
 		Location:  location,
 		Timestamp: time.Now().Unix(),
-		Evidence:  evidence,
+		Evidence:  publicEvidence,
 		Verified:  false,
 		TxHash:    stub.GetTxID(),
 	}
-	
+
 	err := shim.PutStateAsJSON(stub, provenanceID, provenance)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to record provenance: %s", err.Error()))
 	}
-	
+
+	if err := indexProvenanceByProduct(stub, productID, provenance.Timestamp, provenanceID); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to index provenance: %s", err.Error()))
+	}
+
 	return shim.Success([]byte(fmt.Sprintf("Provenance recorded: %s", provenanceID)))
 }
 
+// verifyPrivateEvidence re-hashes provenanceID's private evidence payload
+// in collection and compares it against the SHA-256 commitment stored on
+// the public ProvenanceRecord, so a verifier with access to the collection
+// can prove the on-chain record matches without the payload ever having to
+// leave private data to be checked.
+func (cc *SupplyChainChaincode) verifyPrivateEvidence(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 2); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	provenanceID := args[0]
+	collection := args[1]
+
+	var provenance ProvenanceRecord
+	if err := shim.GetStateAsJSON(stub, provenanceID, &provenance); err != nil {
+		return shim.Error(fmt.Sprintf("Provenance record not found: %s", provenanceID))
+	}
+
+	committedHash, _ := provenance.Evidence["evidenceHash"].(string)
+	if committedHash == "" {
+		return shim.Error(fmt.Sprintf("Provenance record %s has no private evidence commitment", provenanceID))
+	}
+
+	evidenceBytes, err := stub.GetPrivateData(collection, provenanceID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to read private evidence: %s", err.Error()))
+	}
+	if evidenceBytes == nil {
+		return shim.Error(fmt.Sprintf("No private evidence found for %s in collection %s", provenanceID, collection))
+	}
+
+	hash := sha256.Sum256(evidenceBytes)
+	actualHash := hex.EncodeToString(hash[:])
+
+	result := map[string]interface{}{
+		"provenanceID": provenanceID,
+		"collection":   collection,
+		"match":        actualHash == committedHash,
+	}
+
+	responseBytes, err := json.Marshal(result)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
+// assetHistory replays the version chain for key via GetHistoryForKey and
+// returns it as a JSON-friendly slice, oldest modification first.
+func assetHistory(stub shim.ChaincodeStubInterface, key string) ([]map[string]interface{}, error) {
+	iterator, err := stub.GetHistoryForKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history for %s: %w", key, err)
+	}
+	defer iterator.Close()
+
+	history := []map[string]interface{}{}
+	for iterator.HasNext() {
+		mod, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history entry for %s: %w", key, err)
+		}
+
+		entry := map[string]interface{}{
+			"txId":      mod.TxID,
+			"timestamp": mod.Timestamp,
+			"isDelete":  mod.IsDelete,
+		}
+		if !mod.IsDelete {
+			var value interface{}
+			if err := json.Unmarshal(mod.Value, &value); err == nil {
+				entry["value"] = value
+			} else {
+				entry["value"] = string(mod.Value)
+			}
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
+// getProductHistory returns the full version chain for a product, oldest
+// modification first, so auditors can see who changed it and when.
+func (cc *SupplyChainChaincode) getProductHistory(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+
+	history, err := assetHistory(stub, "product:"+productID)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal history: %s", err.Error()))
+	}
+
+	return shim.Success(historyJSON)
+}
+
+// getShipmentHistory returns the full version chain for a shipment, oldest
+// modification first.
+func (cc *SupplyChainChaincode) getShipmentHistory(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	shipmentID := args[0]
+
+	history, err := assetHistory(stub, "shipment:"+shipmentID)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal history: %s", err.Error()))
+	}
+
+	return shim.Success(historyJSON)
+}
+
+// getProvenanceRecordHistory returns the full version chain for a single
+// provenance record, oldest modification first (most are write-once, but
+// verifyProvenance can still update one after the fact).
+func (cc *SupplyChainChaincode) getProvenanceRecordHistory(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	provenanceID := args[0]
+
+	history, err := assetHistory(stub, provenanceID)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal history: %s", err.Error()))
+	}
+
+	return shim.Success(historyJSON)
+}
+
+// ProvenanceHistoryEntry pairs a product's current ProvenanceRecord with its
+// full ledger write history, so auditors can see not just what a record
+// says now but every prior state it passed through (e.g. before and after
+// verifyProvenance flipped Verified to true).
+type ProvenanceHistoryEntry struct {
+	ProvenanceRecord
+	History []map[string]interface{} `json:"history"`
+}
+
+// getProvenanceHistory returns productID's full ordered chain of
+// ProvenanceRecords, using the provenanceByProduct composite-key index to
+// find the records and GetHistoryForKey on each to attach its ledger
+// write/verification history, so auditors can walk a product's complete
+// lineage without scanning every provenance key in the ledger.
+func (cc *SupplyChainChaincode) getProvenanceHistory(stub shim.ChaincodeStubInterface, args []string) shim.Response {
+	if err := shim.ValidateArgs(args, 1); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	productID := args[0]
+
+	ids, err := provenanceIDsForProduct(stub, productID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get provenance chain: %s", err.Error()))
+	}
+
+	entries := make([]ProvenanceHistoryEntry, 0, len(ids))
+	for _, id := range ids {
+		var record ProvenanceRecord
+		if err := shim.GetStateAsJSON(stub, id, &record); err != nil {
+			continue
+		}
+
+		history, err := assetHistory(stub, id)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get history for %s: %s", id, err.Error()))
+		}
+
+		entries = append(entries, ProvenanceHistoryEntry{ProvenanceRecord: record, History: history})
+	}
+
+	responseBytes, err := json.Marshal(entries)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal response: %s", err.Error()))
+	}
+
+	return shim.Success(responseBytes)
+}
+
 // main function - entry point for the chaincode
 func main() {
-	err := shim.Start(new(SupplyChainChaincode))
+	chaincode, err := contractapi.NewChaincode(new(SupplyChainContract))
 	if err != nil {
+		log.Fatalf("Error creating Supply Chain Chaincode: %v", err)
+	}
+
+	// SupplyChainContract only exposes typed methods for a handful of
+	// actions so far; every action not yet ported to a typed transaction
+	// method (shipment, escrow, evidence-anchoring, BOM, private-data,
+	// certificate, history, rich-query, and cross-chain operations) still
+	// runs through the legacy Invoke switch via this fallback.
+	chaincode.SetFallback(new(SupplyChainChaincode))
+
+	if err := shim.Start(chaincode); err != nil {
 		log.Fatalf("Error starting Supply Chain Chaincode: %v", err)
 	}
 }
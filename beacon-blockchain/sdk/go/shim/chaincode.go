@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
 
 	pb "github.com/beacon-blockchain/sdk-go/proto"
 )
@@ -17,10 +17,13 @@ import (
 type ChaincodeStub struct {
 	client        pb.ChaincodeShimClient
 	conn          *grpc.ClientConn
+	stream        *peerStream
+	streamCancel  context.CancelFunc
 	transactionID string
 	channelID     string
 	creator       []byte
 	timestamp     int64
+	transient     map[string][]byte
 }
 
 // Chaincode interface that all Go chaincodes must implement
@@ -42,6 +45,15 @@ type ChaincodeStubInterface interface {
 	// Range queries
 	GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error)
 	GetStateByPartialCompositeKey(objectType string, keys []string) (StateQueryIteratorInterface, error)
+	GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (StateQueryIteratorInterface, *QueryResponseMetadata, error)
+	GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (StateQueryIteratorInterface, *QueryResponseMetadata, error)
+
+	// Rich (CouchDB Mango-style) queries
+	GetQueryResult(query string) (StateQueryIteratorInterface, error)
+	GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (StateQueryIteratorInterface, *QueryResponseMetadata, error)
+
+	// History
+	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
 
 	// Transaction context
 	GetTxID() string
@@ -51,6 +63,7 @@ type ChaincodeStubInterface interface {
 
 	// Event operations
 	SetEvent(name string, payload []byte) error
+	SubscribeEvents(filter EventFilter) (EventSubscription, error)
 
 	// Logging
 	LogMessage(level LogLevel, message string) error
@@ -59,6 +72,19 @@ type ChaincodeStubInterface interface {
 	GetFunctionAndParameters() (string, []string)
 	GetStringArgs() []string
 	GetArgs() [][]byte
+
+	// Cross-chaincode invocation
+	InvokeChaincode(chaincodeName string, args [][]byte, channel string) Response
+
+	// Private data collections
+	GetPrivateData(collection, key string) ([]byte, error)
+	PutPrivateData(collection, key string, value []byte) error
+	DelPrivateData(collection, key string) error
+	GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error)
+	GetPrivateDataHashByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error)
+
+	// Transient data
+	GetTransient() (map[string][]byte, error)
 }
 
 // Response structure for chaincode functions
@@ -81,6 +107,31 @@ type KeyValue struct {
 	Value []byte
 }
 
+// HistoryQueryIteratorInterface iterates over a key's version history, as
+// returned by GetHistoryForKey, oldest modification first
+type HistoryQueryIteratorInterface interface {
+	HasNext() bool
+	Next() (*KeyModification, error)
+	Close() error
+}
+
+// KeyModification is a single entry in a key's version history: the
+// transaction that wrote it, when, what value it wrote (empty if the
+// transaction deleted the key), and whether it was a delete
+type KeyModification struct {
+	TxID      string
+	Timestamp int64
+	Value     []byte
+	IsDelete  bool
+}
+
+// QueryResponseMetadata carries pagination state for paged range, composite
+// key, and rich-query requests
+type QueryResponseMetadata struct {
+	FetchedRecordsCount int32
+	Bookmark            string
+}
+
 // LogLevel enumeration for logging
 type LogLevel int32
 
@@ -109,41 +160,79 @@ func Error(message string) Response {
 	}
 }
 
-// NewChaincodeStub creates a new chaincode stub with gRPC connection
+// NewChaincodeStub creates a new chaincode stub with gRPC connection,
+// reading TLS and auth settings from the BEACON_TLS_*/BEACON_AUTH_TOKEN
+// environment variables
 func NewChaincodeStub() (*ChaincodeStub, error) {
-	// Get gRPC server address from environment
-	grpcAddr := os.Getenv("BEACON_GRPC_ADDRESS")
+	return NewChaincodeStubWithConfig(configFromEnv())
+}
+
+// NewChaincodeStubWithConfig creates a new chaincode stub dialed
+// according to cfg, enabling (mutual) TLS and/or bearer-token
+// authentication when the corresponding fields are set
+func NewChaincodeStubWithConfig(cfg Config) (*ChaincodeStub, error) {
+	grpcAddr := cfg.GRPCAddress
 	if grpcAddr == "" {
 		grpcAddr = "127.0.0.1:9090" // Default address
 	}
 
+	transportCreds, err := cfg.transportCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(transportCreds)}
+	if cfg.AuthToken != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials{
+			token:         cfg.AuthToken,
+			transactionID: os.Getenv("BEACON_TRANSACTION_ID"),
+			requireTLS:    cfg.TLSRootCAsFile != "",
+		}))
+	}
+
 	// Establish gRPC connection
-	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(grpcAddr, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
 	}
 
 	client := pb.NewChaincodeShimClient(conn)
 
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := newPeerStream(streamCtx, client)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+
 	stub := &ChaincodeStub{
 		client:        client,
 		conn:          conn,
+		stream:        stream,
 		transactionID: os.Getenv("BEACON_TRANSACTION_ID"),
 		channelID:     "beacon", // Default channel
 	}
+	stub.streamCancel = cancel
 
 	// Initialize transaction context
 	err = stub.initializeContext()
 	if err != nil {
-		conn.Close()
+		stub.Close()
 		return nil, fmt.Errorf("failed to initialize context: %w", err)
 	}
 
 	return stub, nil
 }
 
-// Close closes the gRPC connection
+// Close closes the peer stream and the underlying gRPC connection
 func (s *ChaincodeStub) Close() error {
+	if s.stream != nil {
+		s.stream.Close()
+	}
+	if s.streamCancel != nil {
+		s.streamCancel()
+	}
 	if s.conn != nil {
 		return s.conn.Close()
 	}
@@ -157,32 +246,48 @@ func (s *ChaincodeStub) initializeContext() error {
 
 	// Get transaction ID
 	if s.transactionID == "" {
-		resp, err := s.client.GetTransactionID(ctx, &pb.Empty{})
+		reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_TRANSACTION_ID, s.transactionID, s.channelID, &pb.Empty{})
 		if err != nil {
 			return fmt.Errorf("failed to get transaction ID: %w", err)
 		}
+		resp := &pb.GetTransactionIDResponse{}
+		if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+			return fmt.Errorf("failed to unmarshal transaction ID reply: %w", err)
+		}
 		s.transactionID = resp.TransactionId
 	}
 
 	// Get channel ID
-	resp, err := s.client.GetChannelID(ctx, &pb.Empty{})
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_CHANNEL_ID, s.transactionID, s.channelID, &pb.Empty{})
 	if err != nil {
 		return fmt.Errorf("failed to get channel ID: %w", err)
 	}
-	s.channelID = resp.ChannelId
+	channelResp := &pb.GetChannelIDResponse{}
+	if err := proto.Unmarshal(reply.Payload, channelResp); err != nil {
+		return fmt.Errorf("failed to unmarshal channel ID reply: %w", err)
+	}
+	s.channelID = channelResp.ChannelId
 
 	// Get creator
-	creatorResp, err := s.client.GetCreator(ctx, &pb.Empty{})
+	reply, err = s.stream.call(ctx, pb.ChaincodeMessage_GET_CREATOR, s.transactionID, s.channelID, &pb.Empty{})
 	if err != nil {
 		return fmt.Errorf("failed to get creator: %w", err)
 	}
+	creatorResp := &pb.GetCreatorResponse{}
+	if err := proto.Unmarshal(reply.Payload, creatorResp); err != nil {
+		return fmt.Errorf("failed to unmarshal creator reply: %w", err)
+	}
 	s.creator = creatorResp.Creator
 
 	// Get timestamp
-	tsResp, err := s.client.GetTransactionTimestamp(ctx, &pb.Empty{})
+	reply, err = s.stream.call(ctx, pb.ChaincodeMessage_GET_TRANSACTION_TIMESTAMP, s.transactionID, s.channelID, &pb.Empty{})
 	if err != nil {
 		return fmt.Errorf("failed to get timestamp: %w", err)
 	}
+	tsResp := &pb.GetTransactionTimestampResponse{}
+	if err := proto.Unmarshal(reply.Payload, tsResp); err != nil {
+		return fmt.Errorf("failed to unmarshal timestamp reply: %w", err)
+	}
 	s.timestamp = tsResp.Timestamp
 
 	return nil
@@ -193,11 +298,16 @@ func (s *ChaincodeStub) GetState(key string) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := s.client.GetState(ctx, &pb.GetStateRequest{Key: key})
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_STATE, s.transactionID, s.channelID, &pb.GetStateRequest{Key: key})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get state for key %s: %w", key, err)
 	}
 
+	resp := &pb.GetStateResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal get state reply for key %s: %w", key, err)
+	}
+
 	if !resp.Found {
 		return nil, nil // Key not found
 	}
@@ -210,7 +320,7 @@ func (s *ChaincodeStub) PutState(key string, value []byte) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := s.client.PutState(ctx, &pb.PutStateRequest{
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_PUT_STATE, s.transactionID, s.channelID, &pb.PutStateRequest{
 		Key:   key,
 		Value: value,
 	})
@@ -218,6 +328,10 @@ func (s *ChaincodeStub) PutState(key string, value []byte) error {
 		return fmt.Errorf("failed to put state for key %s: %w", key, err)
 	}
 
+	resp := &pb.PutStateResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal put state reply for key %s: %w", key, err)
+	}
 	if !resp.Success {
 		return fmt.Errorf("put state failed: %s", resp.Error)
 	}
@@ -230,11 +344,15 @@ func (s *ChaincodeStub) DelState(key string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := s.client.DeleteState(ctx, &pb.DeleteStateRequest{Key: key})
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_DELETE_STATE, s.transactionID, s.channelID, &pb.DeleteStateRequest{Key: key})
 	if err != nil {
 		return fmt.Errorf("failed to delete state for key %s: %w", key, err)
 	}
 
+	resp := &pb.DeleteStateResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal delete state reply for key %s: %w", key, err)
+	}
 	if !resp.Success {
 		return fmt.Errorf("delete state failed: %s", resp.Error)
 	}
@@ -247,7 +365,7 @@ func (s *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryIter
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := s.client.GetStateByRange(ctx, &pb.GetStateByRangeRequest{
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_STATE_BY_RANGE, s.transactionID, s.channelID, &pb.GetStateByRangeRequest{
 		StartKey: startKey,
 		EndKey:   endKey,
 	})
@@ -255,6 +373,11 @@ func (s *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryIter
 		return nil, fmt.Errorf("failed to get state by range: %w", err)
 	}
 
+	resp := &pb.GetStateByRangeResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal get state by range reply: %w", err)
+	}
+
 	return &StateQueryIterator{
 		results: resp.Results,
 		index:   0,
@@ -266,7 +389,7 @@ func (s *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, keys []
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := s.client.GetStateByPartialCompositeKey(ctx, &pb.GetStateByPartialCompositeKeyRequest{
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_STATE_BY_PARTIAL_COMPOSITE_KEY, s.transactionID, s.channelID, &pb.GetStateByPartialCompositeKeyRequest{
 		ObjectType: objectType,
 		Keys:       keys,
 	})
@@ -274,12 +397,116 @@ func (s *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, keys []
 		return nil, fmt.Errorf("failed to get state by partial composite key: %w", err)
 	}
 
+	resp := &pb.GetStateByPartialCompositeKeyResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal get state by partial composite key reply: %w", err)
+	}
+
 	return &StateQueryIterator{
 		results: resp.Results,
 		index:   0,
 	}, nil
 }
 
+// GetStateByRangeWithPagination returns a page of keys between startKey and
+// endKey, along with a bookmark that can be passed back in to fetch the
+// next page
+func (s *ChaincodeStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (StateQueryIteratorInterface, *QueryResponseMetadata, error) {
+	return s.pagedQuery(pb.ChaincodeMessage_GET_STATE_BY_RANGE, &pb.GetStateByRangeRequest{
+		StartKey: startKey,
+		EndKey:   endKey,
+		PageSize: pageSize,
+		Bookmark: bookmark,
+	})
+}
+
+// GetStateByPartialCompositeKeyWithPagination returns a page of keys
+// matching the partial composite key, along with a continuation bookmark
+func (s *ChaincodeStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (StateQueryIteratorInterface, *QueryResponseMetadata, error) {
+	return s.pagedQuery(pb.ChaincodeMessage_GET_STATE_BY_PARTIAL_COMPOSITE_KEY, &pb.GetStateByPartialCompositeKeyRequest{
+		ObjectType: objectType,
+		Keys:       keys,
+		PageSize:   pageSize,
+		Bookmark:   bookmark,
+	})
+}
+
+// GetQueryResult forwards a CouchDB Mango-style JSON selector to the
+// backing state DB and returns an iterator over the matched key/values
+func (s *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
+	iter, _, err := s.pagedQuery(pb.ChaincodeMessage_RICH_QUERY, &pb.RichQueryRequest{Query: query})
+	return iter, err
+}
+
+// GetQueryResultWithPagination is the paginated variant of GetQueryResult
+func (s *ChaincodeStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (StateQueryIteratorInterface, *QueryResponseMetadata, error) {
+	return s.pagedQuery(pb.ChaincodeMessage_RICH_QUERY, &pb.RichQueryRequest{
+		Query:    query,
+		PageSize: pageSize,
+		Bookmark: bookmark,
+	})
+}
+
+// GetHistoryForKey returns the full version chain for key — every
+// transaction that wrote or deleted it, oldest first — letting callers
+// audit who mutated an asset and when instead of only seeing its current
+// value
+func (s *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_HISTORY_FOR_KEY, s.transactionID, s.channelID, &pb.GetHistoryForKeyRequest{
+		Key: key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history for key %s: %w", key, err)
+	}
+
+	resp := &pb.GetHistoryForKeyResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal history reply for key %s: %w", key, err)
+	}
+
+	return &HistoryQueryIterator{
+		results: resp.Results,
+		index:   0,
+	}, nil
+}
+
+// pagedQuery issues a single page of a range/composite-key/rich query and
+// wraps the response in a pagingStateQueryIterator that transparently
+// fetches the next page once the current one is exhausted
+func (s *ChaincodeStub) pagedQuery(msgType pb.ChaincodeMessage_Type, req proto.Message) (StateQueryIteratorInterface, *QueryResponseMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, msgType, s.transactionID, s.channelID, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to run query: %w", err)
+	}
+
+	resp := &pb.QueryResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal query reply: %w", err)
+	}
+
+	metadata := &QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(resp.Results)),
+		Bookmark:            resp.Bookmark,
+	}
+
+	iter := &pagingStateQueryIterator{
+		stub:     s,
+		msgType:  msgType,
+		req:      req,
+		results:  resp.Results,
+		hasMore:  resp.HasMore,
+		bookmark: resp.Bookmark,
+	}
+
+	return iter, metadata, nil
+}
+
 // GetTxID returns the transaction ID
 func (s *ChaincodeStub) GetTxID() string {
 	return s.transactionID
@@ -306,7 +533,7 @@ func (s *ChaincodeStub) SetEvent(name string, payload []byte) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := s.client.SetEvent(ctx, &pb.SetEventRequest{
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_SET_EVENT, s.transactionID, s.channelID, &pb.SetEventRequest{
 		Name:    name,
 		Payload: payload,
 	})
@@ -314,6 +541,10 @@ func (s *ChaincodeStub) SetEvent(name string, payload []byte) error {
 		return fmt.Errorf("failed to set event: %w", err)
 	}
 
+	resp := &pb.SetEventResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal set event reply: %w", err)
+	}
 	if !resp.Success {
 		return fmt.Errorf("set event failed: %s", resp.Error)
 	}
@@ -327,7 +558,7 @@ func (s *ChaincodeStub) LogMessage(level LogLevel, message string) error {
 	defer cancel()
 
 	pbLevel := pb.LogLevel(level)
-	resp, err := s.client.LogMessage(ctx, &pb.LogMessageRequest{
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_LOG_MESSAGE, s.transactionID, s.channelID, &pb.LogMessageRequest{
 		Level:   pbLevel,
 		Message: message,
 	})
@@ -335,6 +566,10 @@ func (s *ChaincodeStub) LogMessage(level LogLevel, message string) error {
 		return fmt.Errorf("failed to log message: %w", err)
 	}
 
+	resp := &pb.LogMessageResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal log message reply: %w", err)
+	}
 	if !resp.Success {
 		return fmt.Errorf("log message failed")
 	}
@@ -399,6 +634,198 @@ func (iter *StateQueryIterator) Close() error {
 	return nil
 }
 
+// HistoryQueryIterator is a HistoryQueryIteratorInterface over the
+// key-modification history returned by GetHistoryForKey
+type HistoryQueryIterator struct {
+	results []*pb.KeyModification
+	index   int
+}
+
+// HasNext returns true if there are more history entries
+func (iter *HistoryQueryIterator) HasNext() bool {
+	return iter.index < len(iter.results)
+}
+
+// Next returns the next key modification
+func (iter *HistoryQueryIterator) Next() (*KeyModification, error) {
+	if !iter.HasNext() {
+		return nil, fmt.Errorf("no more results")
+	}
+
+	result := iter.results[iter.index]
+	iter.index++
+
+	return &KeyModification{
+		TxID:      result.TxId,
+		Timestamp: result.Timestamp,
+		Value:     result.Value,
+		IsDelete:  result.IsDelete,
+	}, nil
+}
+
+// Close closes the iterator (no-op for in-memory results)
+func (iter *HistoryQueryIterator) Close() error {
+	return nil
+}
+
+// MemoryIterator is a StateQueryIteratorInterface over an already
+// materialized slice of KeyValue pairs. It backs shimtest.MockStub, which
+// has no wire format to decode and so works directly in terms of
+// *KeyValue rather than *pb.KeyValue.
+type MemoryIterator struct {
+	results []*KeyValue
+	index   int
+}
+
+// NewMemoryIterator wraps results as a StateQueryIteratorInterface
+func NewMemoryIterator(results []*KeyValue) *MemoryIterator {
+	return &MemoryIterator{results: results}
+}
+
+// HasNext returns true if there are more results
+func (iter *MemoryIterator) HasNext() bool {
+	return iter.index < len(iter.results)
+}
+
+// Next returns the next key-value pair
+func (iter *MemoryIterator) Next() (*KeyValue, error) {
+	if !iter.HasNext() {
+		return nil, fmt.Errorf("no more results")
+	}
+	result := iter.results[iter.index]
+	iter.index++
+	return result, nil
+}
+
+// Close closes the iterator (no-op for in-memory results)
+func (iter *MemoryIterator) Close() error {
+	return nil
+}
+
+// MemoryHistoryIterator is a HistoryQueryIteratorInterface over an already
+// materialized slice of KeyModifications. It backs shimtest.MockStub, the
+// same way MemoryIterator backs its state queries.
+type MemoryHistoryIterator struct {
+	results []*KeyModification
+	index   int
+}
+
+// NewMemoryHistoryIterator wraps results as a HistoryQueryIteratorInterface
+func NewMemoryHistoryIterator(results []*KeyModification) *MemoryHistoryIterator {
+	return &MemoryHistoryIterator{results: results}
+}
+
+// HasNext returns true if there are more history entries
+func (iter *MemoryHistoryIterator) HasNext() bool {
+	return iter.index < len(iter.results)
+}
+
+// Next returns the next key modification
+func (iter *MemoryHistoryIterator) Next() (*KeyModification, error) {
+	if !iter.HasNext() {
+		return nil, fmt.Errorf("no more results")
+	}
+	result := iter.results[iter.index]
+	iter.index++
+	return result, nil
+}
+
+// Close closes the iterator (no-op for in-memory results)
+func (iter *MemoryHistoryIterator) Close() error {
+	return nil
+}
+
+// pagingStateQueryIterator wraps a single page of range/composite-key/rich
+// query results and lazily fetches the next page from the peer once the
+// current one runs out, so callers can HasNext/Next over an arbitrarily
+// large result set without holding it all in memory at once
+type pagingStateQueryIterator struct {
+	stub     *ChaincodeStub
+	msgType  pb.ChaincodeMessage_Type
+	req      proto.Message
+	results  []*pb.KeyValue
+	index    int
+	hasMore  bool
+	bookmark string
+	closed   bool
+}
+
+// HasNext returns true if there are more results, fetching the next page
+// from the peer if the current page has been exhausted
+func (iter *pagingStateQueryIterator) HasNext() bool {
+	if iter.index < len(iter.results) {
+		return true
+	}
+	if iter.closed || !iter.hasMore {
+		return false
+	}
+
+	if err := iter.fetchNextPage(); err != nil {
+		return false
+	}
+	return iter.index < len(iter.results)
+}
+
+// fetchNextPage requests the next page using the bookmark returned by the
+// previous response
+func (iter *pagingStateQueryIterator) fetchNextPage() error {
+	setPaginationFields(iter.req, iter.bookmark)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reply, err := iter.stub.stream.call(ctx, iter.msgType, iter.stub.transactionID, iter.stub.channelID, iter.req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch next page: %w", err)
+	}
+
+	resp := &pb.QueryResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal next page reply: %w", err)
+	}
+
+	iter.results = resp.Results
+	iter.index = 0
+	iter.hasMore = resp.HasMore
+	iter.bookmark = resp.Bookmark
+
+	return nil
+}
+
+// Next returns the next key-value pair
+func (iter *pagingStateQueryIterator) Next() (*KeyValue, error) {
+	if !iter.HasNext() {
+		return nil, fmt.Errorf("no more results")
+	}
+
+	result := iter.results[iter.index]
+	iter.index++
+
+	return &KeyValue{
+		Key:   result.Key,
+		Value: result.Value,
+	}, nil
+}
+
+// Close marks the iterator as exhausted so it stops fetching further pages
+func (iter *pagingStateQueryIterator) Close() error {
+	iter.closed = true
+	return nil
+}
+
+// setPaginationFields stamps the bookmark for the next page onto whichever
+// concrete request type backs a paged query
+func setPaginationFields(req proto.Message, bookmark string) {
+	switch r := req.(type) {
+	case *pb.GetStateByRangeRequest:
+		r.Bookmark = bookmark
+	case *pb.GetStateByPartialCompositeKeyRequest:
+		r.Bookmark = bookmark
+	case *pb.RichQueryRequest:
+		r.Bookmark = bookmark
+	}
+}
+
 // Start is the main entry point for chaincodes
 func Start(cc Chaincode) error {
 	stub, err := NewChaincodeStub()
@@ -0,0 +1,116 @@
+package shim
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// insecureCredentials is the fallback used when no TLS material is
+// configured, matching the previous unconditional insecure.NewCredentials().
+func insecureCredentials() credentials.TransportCredentials {
+	return insecure.NewCredentials()
+}
+
+// Config controls how NewChaincodeStubWithConfig dials the peer. All
+// fields are optional; an empty Config behaves like NewChaincodeStub
+// (insecure, unauthenticated).
+type Config struct {
+	// GRPCAddress overrides BEACON_GRPC_ADDRESS / the default 127.0.0.1:9090.
+	GRPCAddress string
+
+	// TLSRootCAsFile, when set, enables TLS using the given PEM CA bundle
+	// to verify the peer's certificate.
+	TLSRootCAsFile string
+
+	// TLSClientCertFile and TLSClientKeyFile, when both set, present a
+	// client certificate for mutual TLS.
+	TLSClientCertFile string
+	TLSClientKeyFile  string
+
+	// ServerNameOverride overrides the server name used for TLS
+	// verification (useful when dialing by IP).
+	ServerNameOverride string
+
+	// AuthToken, when set, is stamped as `authorization: Bearer <token>`
+	// on every outbound call and stream.
+	AuthToken string
+}
+
+// configFromEnv builds a Config from BEACON_TLS_* and BEACON_AUTH_TOKEN
+// environment variables, mirroring the Config field names
+func configFromEnv() Config {
+	return Config{
+		GRPCAddress:        os.Getenv("BEACON_GRPC_ADDRESS"),
+		TLSRootCAsFile:     os.Getenv("BEACON_TLS_ROOT_CAS_FILE"),
+		TLSClientCertFile:  os.Getenv("BEACON_TLS_CLIENT_CERT_FILE"),
+		TLSClientKeyFile:   os.Getenv("BEACON_TLS_CLIENT_KEY_FILE"),
+		ServerNameOverride: os.Getenv("BEACON_TLS_SERVER_NAME_OVERRIDE"),
+		AuthToken:          os.Getenv("BEACON_AUTH_TOKEN"),
+	}
+}
+
+// transportCredentials builds the grpc.WithTransportCredentials option
+// implied by cfg: TLS (optionally mutual) when CA/cert material is
+// present, insecure otherwise.
+func (cfg Config) transportCredentials() (credentials.TransportCredentials, error) {
+	if cfg.TLSRootCAsFile == "" {
+		return insecureCredentials(), nil
+	}
+
+	caBytes, err := os.ReadFile(cfg.TLSRootCAsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS root CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("failed to parse TLS root CA file: %s", cfg.TLSRootCAsFile)
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		ServerName: cfg.ServerNameOverride,
+	}
+
+	if cfg.TLSClientCertFile != "" && cfg.TLSClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSClientCertFile, cfg.TLSClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// tokenCredentials implements grpc.PerRPCCredentials, stamping a bearer
+// token (and the active transaction ID, when known) on every outbound
+// call and stream.
+type tokenCredentials struct {
+	token         string
+	transactionID string
+	requireTLS    bool
+}
+
+// GetRequestMetadata returns the per-RPC metadata for a single call
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	md := map[string]string{
+		"authorization": "Bearer " + c.token,
+	}
+	if c.transactionID != "" {
+		md["x-beacon-transaction-id"] = c.transactionID
+	}
+	return md, nil
+}
+
+// RequireTransportSecurity reports whether these credentials may only be
+// used over an encrypted channel
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
@@ -0,0 +1,159 @@
+// Package contractapi ports the core ergonomics of Hyperledger Fabric's
+// fabric-contract-api-go on top of this SDK's own shim: chaincode authors
+// write typed transaction methods instead of hand-parsing a string-array
+// args slice and dispatching on a function-name switch. Parameters and
+// return values are marshalled to/from JSON by reflection, the same way
+// shim/table ports the Fabric table API and shim/policy ports a policy
+// evaluation engine — as a from-scratch implementation against this SDK's
+// own shim.ChaincodeStubInterface, not a dependency on the upstream
+// hyperledger/fabric-contract-api-go module this SDK doesn't otherwise use.
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// TransactionContextInterface is passed as the first parameter to every
+// contract transaction method, giving it access to the stub for the
+// current invocation.
+type TransactionContextInterface interface {
+	GetStub() shim.ChaincodeStubInterface
+}
+
+// TransactionContext is the default TransactionContextInterface
+// implementation threaded through every transaction call.
+type TransactionContext struct {
+	stub shim.ChaincodeStubInterface
+}
+
+// GetStub returns the stub for the current transaction.
+func (ctx *TransactionContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
+
+// Contract is the base type a typed chaincode contract embeds, mirroring
+// contractapi.Contract in the upstream Fabric SDK. It carries no behavior
+// of its own; it exists so NewChaincode can recognize a contract value by
+// type rather than by convention.
+type Contract struct{}
+
+// ChaincodeContract adapts one or more typed contracts to shim.Chaincode,
+// dispatching GetFunctionAndParameters() by reflecting over each
+// contract's exported methods.
+type ChaincodeContract struct {
+	methods  map[string]reflect.Value
+	fallback shim.Chaincode
+}
+
+// NewChaincode builds a shim.Chaincode that dispatches each invoke to the
+// matching exported method (by name) on one of the given typed contracts.
+// Every contract method must take a *TransactionContext first, followed by
+// its typed parameters, and return either (error) or (T, error); T is
+// JSON-marshalled as the response payload.
+func NewChaincode(contracts ...interface{}) (*ChaincodeContract, error) {
+	cc := &ChaincodeContract{methods: make(map[string]reflect.Value)}
+
+	for _, contract := range contracts {
+		v := reflect.ValueOf(contract)
+		t := v.Type()
+		for i := 0; i < t.NumMethod(); i++ {
+			m := t.Method(i)
+			if _, exists := cc.methods[m.Name]; exists {
+				return nil, fmt.Errorf("duplicate transaction name: %s", m.Name)
+			}
+			cc.methods[m.Name] = v.Method(i)
+		}
+	}
+
+	return cc, nil
+}
+
+// SetFallback registers a legacy shim.Chaincode to handle any function
+// name that isn't a registered typed transaction method, so a contract
+// can be adopted incrementally — porting one action to a typed method at a
+// time — without taking every not-yet-ported action out of service.
+func (cc *ChaincodeContract) SetFallback(fallback shim.Chaincode) {
+	cc.fallback = fallback
+}
+
+// Init delegates to the fallback chaincode's Init, if one is registered,
+// so legacy setup (e.g. seeding system configuration) still runs; contracts
+// with no fallback that need their own setup expose a typed Init-style
+// transaction and call it explicitly after instantiation.
+func (cc *ChaincodeContract) Init(stub shim.ChaincodeStubInterface) shim.Response {
+	if cc.fallback != nil {
+		return cc.fallback.Init(stub)
+	}
+	return shim.Success(nil)
+}
+
+// Invoke resolves GetFunctionAndParameters() to a registered transaction
+// method, JSON-unmarshals each argument into that method's declared
+// parameter type (or assigns it directly for a plain string parameter),
+// and JSON-marshals the method's non-error return value as the payload.
+// A function name with no registered typed method falls through to the
+// fallback chaincode's Invoke, if one is registered.
+func (cc *ChaincodeContract) Invoke(stub shim.ChaincodeStubInterface) shim.Response {
+	function, args := stub.GetFunctionAndParameters()
+
+	method, ok := cc.methods[function]
+	if !ok {
+		if cc.fallback != nil {
+			return cc.fallback.Invoke(stub)
+		}
+		return shim.Error(fmt.Sprintf("Unknown transaction: %s", function))
+	}
+
+	methodType := method.Type()
+	expectedArgs := methodType.NumIn() - 1
+	if len(args) != expectedArgs {
+		return shim.Error(fmt.Sprintf("%s expects %d parameter(s), got %d", function, expectedArgs, len(args)))
+	}
+
+	callArgs := make([]reflect.Value, 0, methodType.NumIn())
+	callArgs = append(callArgs, reflect.ValueOf(&TransactionContext{stub: stub}))
+
+	for i := 0; i < expectedArgs; i++ {
+		paramType := methodType.In(i + 1)
+		argPtr := reflect.New(paramType)
+
+		if paramType.Kind() == reflect.String {
+			argPtr.Elem().SetString(args[i])
+		} else if err := json.Unmarshal([]byte(args[i]), argPtr.Interface()); err != nil {
+			return shim.Error(fmt.Sprintf("Invalid parameter %d for %s: %s", i, function, err.Error()))
+		}
+
+		callArgs = append(callArgs, argPtr.Elem())
+	}
+
+	results := method.Call(callArgs)
+
+	var dataVal, errVal reflect.Value
+	switch len(results) {
+	case 1:
+		errVal = results[0]
+	case 2:
+		dataVal, errVal = results[0], results[1]
+	default:
+		return shim.Error(fmt.Sprintf("%s has an unsupported return signature", function))
+	}
+
+	if !errVal.IsNil() {
+		return shim.Error(errVal.Interface().(error).Error())
+	}
+
+	if !dataVal.IsValid() || (dataVal.Kind() == reflect.Ptr && dataVal.IsNil()) {
+		return shim.Success(nil)
+	}
+
+	payload, err := json.Marshal(dataVal.Interface())
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal result of %s: %s", function, err.Error()))
+	}
+
+	return shim.Success(payload)
+}
@@ -0,0 +1,159 @@
+package shim
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/beacon-blockchain/sdk-go/proto"
+)
+
+// EventFilter scopes an event subscription
+type EventFilter struct {
+	// NameGlob restricts delivery to events whose name matches the glob
+	// (e.g. "Gateway*"). Empty matches everything.
+	NameGlob string
+
+	// ChannelID restricts delivery to a single channel. Empty defaults to
+	// the subscribing chaincode's own channel.
+	ChannelID string
+
+	// StartFromBlock replays events from the given block height before
+	// switching to live delivery. Zero means "only new events".
+	StartFromBlock uint64
+}
+
+// Event is a single delivered chaincode event
+type Event struct {
+	Name      string
+	Payload   []byte
+	ChannelID string
+	TxID      string
+	BlockNum  uint64
+}
+
+// EventSubscription is a live handle to a SubscribeEvents call
+type EventSubscription interface {
+	// Recv blocks until the next matching event arrives, the subscription
+	// errors out, or it is unsubscribed
+	Recv() (*Event, error)
+
+	// Err returns a channel on which the terminal error, if any, is sent
+	// once the subscription can no longer deliver events; the channel is
+	// never closed
+	Err() <-chan error
+
+	// Unsubscribe tears down the subscription and stops the background
+	// goroutine
+	Unsubscribe()
+}
+
+// eventSubscription implements EventSubscription with a single goroutine
+// reading the server stream, a bounded channel for backpressure, and
+// automatic re-subscribe on transient gRPC errors
+type eventSubscription struct {
+	stub   *ChaincodeStub
+	filter EventFilter
+
+	events chan *Event
+	errCh  chan error
+	cancel context.CancelFunc
+}
+
+// SubscribeEvents opens a server-streaming subscription for committed
+// events, block events, and state-change notifications matching filter
+func (s *ChaincodeStub) SubscribeEvents(filter EventFilter) (EventSubscription, error) {
+	if filter.ChannelID == "" {
+		filter.ChannelID = s.channelID
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := &eventSubscription{
+		stub:   s,
+		filter: filter,
+		events: make(chan *Event, 256), // bounded: slow consumers apply backpressure, not unbounded memory growth
+		errCh:  make(chan error, 1),
+		cancel: cancel,
+	}
+
+	stream, err := s.client.SubscribeEvents(ctx, &pb.SubscribeEventsRequest{
+		NameGlob:       filter.NameGlob,
+		ChannelId:      filter.ChannelID,
+		StartFromBlock: filter.StartFromBlock,
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	go sub.recvLoop(ctx, stream)
+
+	return sub, nil
+}
+
+// recvLoop pumps events from the gRPC stream into the bounded channel,
+// transparently re-subscribing on transient errors
+func (sub *eventSubscription) recvLoop(ctx context.Context, stream pb.ChaincodeShim_SubscribeEventsClient) {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				close(sub.events)
+				return
+			}
+
+			// Transient error: re-subscribe rather than giving up outright.
+			newStream, resubErr := sub.stub.client.SubscribeEvents(ctx, &pb.SubscribeEventsRequest{
+				NameGlob:       sub.filter.NameGlob,
+				ChannelId:      sub.filter.ChannelID,
+				StartFromBlock: sub.filter.StartFromBlock,
+			})
+			if resubErr != nil {
+				sub.errCh <- fmt.Errorf("event subscription failed and could not resubscribe: %w", err)
+				close(sub.events)
+				return
+			}
+			stream = newStream
+			continue
+		}
+
+		event := &Event{
+			Name:      msg.Name,
+			Payload:   msg.Payload,
+			ChannelID: msg.ChannelId,
+			TxID:      msg.TxId,
+			BlockNum:  msg.BlockNum,
+		}
+
+		select {
+		case sub.events <- event:
+		case <-ctx.Done():
+			close(sub.events)
+			return
+		}
+	}
+}
+
+// Recv blocks until the next matching event arrives
+func (sub *eventSubscription) Recv() (*Event, error) {
+	event, ok := <-sub.events
+	if !ok {
+		select {
+		case err := <-sub.errCh:
+			return nil, err
+		default:
+			return nil, fmt.Errorf("event subscription closed")
+		}
+	}
+	return event, nil
+}
+
+// Err returns the channel the terminal subscription error is delivered on
+func (sub *eventSubscription) Err() <-chan error {
+	return sub.errCh
+}
+
+// Unsubscribe tears down the subscription
+func (sub *eventSubscription) Unsubscribe() {
+	sub.cancel()
+}
@@ -0,0 +1,76 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// evaluateConditions requires every condition string to hold (AND
+// semantics); an empty slice always holds. Each condition is one of:
+//
+//	attr==value     equality against ctx.Attributes[attr]
+//	time:HH:MM-HH:MM ctx.TimeOfDay falls within the window (wraps past midnight)
+//	cidr:a.b.c.d/n  ctx.SourceIP falls within the CIDR block
+func evaluateConditions(conditions []string, ctx EvalContext) (bool, error) {
+	for _, condition := range conditions {
+		ok, err := evaluateCondition(condition, ctx)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func evaluateCondition(condition string, ctx EvalContext) (bool, error) {
+	switch {
+	case strings.HasPrefix(condition, "time:"):
+		return evaluateTimeWindow(strings.TrimPrefix(condition, "time:"), ctx.TimeOfDay)
+
+	case strings.HasPrefix(condition, "cidr:"):
+		return evaluateCIDR(strings.TrimPrefix(condition, "cidr:"), ctx.SourceIP)
+
+	case strings.Contains(condition, "=="):
+		parts := strings.SplitN(condition, "==", 2)
+		attr, want := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		return ctx.Attributes[attr] == want, nil
+
+	default:
+		return false, fmt.Errorf("unrecognized condition expression: %q", condition)
+	}
+}
+
+func evaluateTimeWindow(window, timeOfDay string) (bool, error) {
+	parts := strings.SplitN(window, "-", 2)
+	if len(parts) != 2 {
+		return false, fmt.Errorf("invalid time window %q, want HH:MM-HH:MM", window)
+	}
+	start, end := parts[0], parts[1]
+
+	if timeOfDay == "" {
+		return false, fmt.Errorf("no time-of-day available to evaluate window %q", window)
+	}
+	if start <= end {
+		return timeOfDay >= start && timeOfDay <= end, nil
+	}
+	// window wraps past midnight, e.g. 22:00-06:00
+	return timeOfDay >= start || timeOfDay <= end, nil
+}
+
+func evaluateCIDR(cidr, sourceIP string) (bool, error) {
+	if sourceIP == "" {
+		return false, fmt.Errorf("no source IP available to evaluate CIDR %q", cidr)
+	}
+	_, block, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+	ip := net.ParseIP(sourceIP)
+	if ip == nil {
+		return false, fmt.Errorf("invalid source IP %q", sourceIP)
+	}
+	return block.Contains(ip), nil
+}
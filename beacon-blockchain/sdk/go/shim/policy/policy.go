@@ -0,0 +1,187 @@
+// Package policy implements a small, pluggable access-control evaluation
+// engine for rule-based policies: a built-in condition DSL (attribute
+// equality, time-of-day windows, IP CIDR match) with conflict resolution
+// across matching rules, and room to delegate to an external evaluator
+// (CEL, Rego, ...) without changing the caller's contract.
+package policy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ConflictStrategy selects how multiple applicable rules within a policy
+// are reconciled into a single decision
+type ConflictStrategy string
+
+const (
+	// DenyOverrides grants access only if no applicable rule explicitly denies it
+	DenyOverrides ConflictStrategy = "deny-overrides"
+	// FirstMatch returns the effect of the first applicable rule, in declaration order
+	FirstMatch ConflictStrategy = "first-match"
+	// Priority returns the effect of the applicable rule with the highest Priority value
+	Priority ConflictStrategy = "priority"
+)
+
+// Effect is the outcome a matching rule contributes to a decision
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Rule is a single access-control rule: match criteria plus condition
+// expressions evaluated against the request context
+type Rule struct {
+	Resource   string   `json:"resource"`
+	Action     string   `json:"action"`
+	Principals []string `json:"principals"`
+	Conditions []string `json:"conditions"`
+	Effect     Effect   `json:"effect,omitempty"`
+	Priority   int      `json:"priority,omitempty"`
+}
+
+// Policy is a named, ordered collection of rules evaluated as a unit
+type Policy struct {
+	ID       string           `json:"id"`
+	Rules    []Rule           `json:"rules"`
+	Strategy ConflictStrategy `json:"strategy,omitempty"`
+}
+
+// EvalContext carries the request-specific facts a rule's conditions are
+// evaluated against
+type EvalContext struct {
+	Principal  string
+	Resource   string
+	Action     string
+	Attributes map[string]string
+	TimeOfDay  string // "HH:MM", local to the evaluating peer
+	SourceIP   string
+}
+
+// Decision is the structured outcome of evaluating a policy
+type Decision struct {
+	Allow         bool   `json:"allow"`
+	MatchedPolicy string `json:"matchedPolicy,omitempty"`
+	MatchedRule   int    `json:"matchedRule"`
+	Priority      int    `json:"priority,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// Evaluator decides access for a single policy. The built-in DefaultEvaluator
+// covers the condition DSL; callers wanting CEL/Rego-style evaluation can
+// supply their own implementation with the same contract
+type Evaluator interface {
+	Evaluate(ctx EvalContext, pol Policy) (Decision, error)
+}
+
+// DefaultEvaluator evaluates rules using the built-in condition DSL
+// (see evaluateConditions) and resolves conflicts per pol.Strategy
+type DefaultEvaluator struct{}
+
+// NewDefaultEvaluator returns the built-in, dependency-free evaluator
+func NewDefaultEvaluator() *DefaultEvaluator {
+	return &DefaultEvaluator{}
+}
+
+type match struct {
+	rule   Rule
+	index  int
+	effect Effect
+}
+
+// Evaluate filters pol.Rules to those matching ctx (resource, action,
+// principal and all conditions), then resolves conflicts among the
+// matches per pol.Strategy. A policy with no matching rule denies.
+func (e *DefaultEvaluator) Evaluate(ctx EvalContext, pol Policy) (Decision, error) {
+	var matches []match
+
+	for i, rule := range pol.Rules {
+		if !resourceMatches(rule.Resource, ctx.Resource) {
+			continue
+		}
+		if !actionMatches(rule.Action, ctx.Action) {
+			continue
+		}
+		if !principalMatches(rule.Principals, ctx.Principal) {
+			continue
+		}
+
+		ok, err := evaluateConditions(rule.Conditions, ctx)
+		if err != nil {
+			return Decision{}, fmt.Errorf("rule %d: %w", i, err)
+		}
+		if !ok {
+			continue
+		}
+
+		effect := rule.Effect
+		if effect == "" {
+			effect = Allow
+		}
+		matches = append(matches, match{rule: rule, index: i, effect: effect})
+	}
+
+	if len(matches) == 0 {
+		return Decision{Allow: false, MatchedPolicy: pol.ID, MatchedRule: -1, Reason: "no matching rule"}, nil
+	}
+
+	strategy := pol.Strategy
+	if strategy == "" {
+		strategy = DenyOverrides
+	}
+
+	switch strategy {
+	case FirstMatch:
+		m := matches[0]
+		return decisionFor(pol.ID, m), nil
+
+	case Priority:
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].rule.Priority > matches[j].rule.Priority
+		})
+		return decisionFor(pol.ID, matches[0]), nil
+
+	case DenyOverrides:
+		fallthrough
+	default:
+		for _, m := range matches {
+			if m.effect == Deny {
+				return decisionFor(pol.ID, m), nil
+			}
+		}
+		return decisionFor(pol.ID, matches[0]), nil
+	}
+}
+
+func decisionFor(policyID string, m match) Decision {
+	reason := fmt.Sprintf("rule %d matched with effect %q", m.index, m.effect)
+	return Decision{
+		Allow:         m.effect == Allow,
+		MatchedPolicy: policyID,
+		MatchedRule:   m.index,
+		Priority:      m.rule.Priority,
+		Reason:        reason,
+	}
+}
+
+func resourceMatches(pattern, resource string) bool {
+	return pattern == "*" || pattern == resource
+}
+
+func actionMatches(pattern, action string) bool {
+	return pattern == "*" || pattern == action
+}
+
+func principalMatches(principals []string, principal string) bool {
+	if len(principals) == 0 {
+		return true
+	}
+	for _, p := range principals {
+		if p == "*" || p == principal {
+			return true
+		}
+	}
+	return false
+}
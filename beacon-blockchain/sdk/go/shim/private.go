@@ -0,0 +1,196 @@
+package shim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/beacon-blockchain/sdk-go/proto"
+)
+
+// InvokeChaincode synchronously calls another chaincode on the same or a
+// different channel, propagating the current transaction/creator so its
+// writes join the same read-write set
+func (s *ChaincodeStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) Response {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if channel == "" {
+		channel = s.channelID
+	}
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_INVOKE_CHAINCODE, s.transactionID, channel, &pb.InvokeChaincodeRequest{
+		ChaincodeName: chaincodeName,
+		Args:          args,
+		ChannelId:     channel,
+	})
+	if err != nil {
+		return Error(fmt.Sprintf("failed to invoke chaincode %s: %s", chaincodeName, err.Error()))
+	}
+
+	resp := &pb.InvokeChaincodeResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return Error(fmt.Sprintf("failed to unmarshal response from chaincode %s: %s", chaincodeName, err.Error()))
+	}
+
+	return Response{
+		Status:  resp.Status,
+		Message: resp.Message,
+		Payload: resp.Payload,
+	}
+}
+
+// GetPrivateData retrieves a value from a private data collection, which
+// lives outside the main ledger but whose hash is endorsed
+func (s *ChaincodeStub) GetPrivateData(collection, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_PRIVATE_DATA, s.transactionID, s.channelID, &pb.GetPrivateDataRequest{
+		Collection: collection,
+		Key:        key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private data for key %s: %w", key, err)
+	}
+
+	resp := &pb.GetPrivateDataResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal private data reply for key %s: %w", key, err)
+	}
+	if !resp.Found {
+		return nil, nil
+	}
+
+	return resp.Value, nil
+}
+
+// PutPrivateData saves a key-value pair to a private data collection
+func (s *ChaincodeStub) PutPrivateData(collection, key string, value []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_PUT_PRIVATE_DATA, s.transactionID, s.channelID, &pb.PutPrivateDataRequest{
+		Collection: collection,
+		Key:        key,
+		Value:      value,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put private data for key %s: %w", key, err)
+	}
+
+	resp := &pb.PutPrivateDataResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal put private data reply for key %s: %w", key, err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("put private data failed: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// DelPrivateData removes a key from a private data collection
+func (s *ChaincodeStub) DelPrivateData(collection, key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_DELETE_PRIVATE_DATA, s.transactionID, s.channelID, &pb.DeletePrivateDataRequest{
+		Collection: collection,
+		Key:        key,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete private data for key %s: %w", key, err)
+	}
+
+	resp := &pb.DeletePrivateDataResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal delete private data reply for key %s: %w", key, err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("delete private data failed: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// GetPrivateDataByRange returns a range query iterator over a private data
+// collection, scoped between startKey and endKey
+func (s *ChaincodeStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_PRIVATE_DATA_BY_RANGE, s.transactionID, s.channelID, &pb.GetPrivateDataByRangeRequest{
+		Collection: collection,
+		StartKey:   startKey,
+		EndKey:     endKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private data by range: %w", err)
+	}
+
+	resp := &pb.GetStateByRangeResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal private data range reply: %w", err)
+	}
+
+	return &StateQueryIterator{
+		results: resp.Results,
+		index:   0,
+	}, nil
+}
+
+// GetPrivateDataHashByRange returns the on-chain hash of each private data
+// entry within the range, without revealing the private values themselves.
+// Useful for verifying off-chain data (or cross-collection consistency)
+// without requesting membership in the collection.
+func (s *ChaincodeStub) GetPrivateDataHashByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH_BY_RANGE, s.transactionID, s.channelID, &pb.GetPrivateDataByRangeRequest{
+		Collection: collection,
+		StartKey:   startKey,
+		EndKey:     endKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private data hashes by range: %w", err)
+	}
+
+	resp := &pb.GetStateByRangeResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal private data hash range reply: %w", err)
+	}
+
+	return &StateQueryIterator{
+		results: resp.Results,
+		index:   0,
+	}, nil
+}
+
+// GetTransient returns the per-invocation transient map populated from the
+// proposal, letting callers pass secrets (passwords, private-data
+// payloads) without persisting them on the transaction itself
+func (s *ChaincodeStub) GetTransient() (map[string][]byte, error) {
+	if s.transient != nil {
+		return s.transient, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := s.stream.call(ctx, pb.ChaincodeMessage_GET_TRANSIENT, s.transactionID, s.channelID, &pb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transient data: %w", err)
+	}
+
+	resp := &pb.GetTransientResponse{}
+	if err := proto.Unmarshal(reply.Payload, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transient data reply: %w", err)
+	}
+
+	s.transient = resp.TransientMap
+	return s.transient, nil
+}
@@ -0,0 +1,47 @@
+package shimtest
+
+import (
+	"fmt"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// MockPeer is a tiny in-memory registry of named chaincodes, letting a
+// MockStub's InvokeChaincode/MockInvokeChaincode dispatch to other
+// chaincodes under test without a real peer routing the call.
+type MockPeer struct {
+	chaincodes map[string]shim.Chaincode
+	stubs      map[string]*MockStub
+}
+
+// NewMockPeer creates an empty chaincode registry
+func NewMockPeer() *MockPeer {
+	return &MockPeer{
+		chaincodes: make(map[string]shim.Chaincode),
+		stubs:      make(map[string]*MockStub),
+	}
+}
+
+// Register makes cc invokable by name
+func (p *MockPeer) Register(name string, cc shim.Chaincode) {
+	p.chaincodes[name] = cc
+	p.stubs[name] = NewMockStub(name, cc)
+}
+
+// Invoke drives the registered chaincode's Invoke against its own
+// MockStub, returning an error Response if no chaincode is registered
+// under name
+func (p *MockPeer) Invoke(name string, args [][]byte) shim.Response {
+	stub, ok := p.stubs[name]
+	if !ok {
+		return shim.Error(fmt.Sprintf("no chaincode registered as %s", name))
+	}
+
+	function := ""
+	if len(args) > 0 {
+		function = string(args[0])
+		args = args[1:]
+	}
+
+	return stub.MockInvoke(function, args)
+}
@@ -0,0 +1,442 @@
+// Package shimtest lets chaincode authors exercise their Init/Invoke
+// logic without a running peer, mirroring Fabric's shimtest ergonomics.
+package shimtest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// recordedEvent is an event captured by SetEvent for later assertions
+type recordedEvent struct {
+	Name    string
+	Payload []byte
+}
+
+// MockStub implements shim.ChaincodeStubInterface entirely in memory
+type MockStub struct {
+	Name string
+	cc   shim.Chaincode
+
+	state      map[string][]byte
+	keyOrder   []string // kept sorted; backs range/composite-key queries
+	privateData map[string]map[string][]byte
+	history    map[string][]*shim.KeyModification // per-key version chain, backs GetHistoryForKey
+
+	transactionID string
+	channelID     string
+	creator       []byte
+	timestamp     int64
+	transient     map[string][]byte
+	args          [][]byte
+	function      string
+
+	events []recordedEvent
+
+	// registered chaincodes other MockStubs can invoke by name, wired up
+	// via MockPeer so MockInvokeChaincode can dispatch to them
+	peer *MockPeer
+
+	// snapshot support for MockTransactionStart/End
+	snapshot map[string][]byte
+}
+
+// NewMockStub creates a MockStub for the given chaincode, ready to drive
+// Init/Invoke calls against an in-memory ledger
+func NewMockStub(name string, cc shim.Chaincode) *MockStub {
+	return &MockStub{
+		Name:        name,
+		cc:          cc,
+		state:       make(map[string][]byte),
+		privateData: make(map[string]map[string][]byte),
+		history:     make(map[string][]*shim.KeyModification),
+		channelID:   "mockchannel",
+		timestamp:   time.Now().Unix(),
+	}
+}
+
+// MockInvoke drives the wrapped chaincode's Invoke with the given
+// function/args set via GetFunctionAndParameters
+func (m *MockStub) MockInvoke(function string, args [][]byte) shim.Response {
+	m.function = function
+	m.args = args
+	m.transactionID = fmt.Sprintf("mocktx-%d", time.Now().UnixNano())
+	return m.cc.Invoke(m)
+}
+
+// MockInit drives the wrapped chaincode's Init
+func (m *MockStub) MockInit(args [][]byte) shim.Response {
+	m.args = args
+	m.transactionID = fmt.Sprintf("mocktx-%d", time.Now().UnixNano())
+	return m.cc.Init(m)
+}
+
+// SetCreator injects the identity bytes subsequent GetCreator calls return
+func (m *MockStub) SetCreator(creator []byte) {
+	m.creator = creator
+}
+
+// SetTransient injects the transient map subsequent GetTransient calls return
+func (m *MockStub) SetTransient(transient map[string][]byte) {
+	m.transient = transient
+}
+
+// GetEvents returns every event recorded via SetEvent since the MockStub
+// was created (or since the last ClearEvents)
+func (m *MockStub) GetEvents() []struct {
+	Name    string
+	Payload []byte
+} {
+	out := make([]struct {
+		Name    string
+		Payload []byte
+	}, len(m.events))
+	for i, e := range m.events {
+		out[i] = struct {
+			Name    string
+			Payload []byte
+		}{Name: e.Name, Payload: e.Payload}
+	}
+	return out
+}
+
+// ClearEvents discards recorded events
+func (m *MockStub) ClearEvents() {
+	m.events = nil
+}
+
+// MockTransactionStart snapshots the current state so a failed
+// transaction can be rolled back with MockTransactionEnd(false)
+func (m *MockStub) MockTransactionStart(txID string) {
+	m.transactionID = txID
+	m.snapshot = make(map[string][]byte, len(m.state))
+	for k, v := range m.state {
+		m.snapshot[k] = append([]byte(nil), v...)
+	}
+}
+
+// MockTransactionEnd commits the transaction if commit is true, or rolls
+// the state back to the MockTransactionStart snapshot otherwise
+func (m *MockStub) MockTransactionEnd(commit bool) {
+	if !commit && m.snapshot != nil {
+		m.state = m.snapshot
+		m.rebuildKeyOrder()
+	}
+	m.snapshot = nil
+}
+
+// RegisterChaincode makes another chaincode callable via
+// InvokeChaincode/MockInvokeChaincode under the given name
+func (m *MockStub) RegisterChaincode(name string, cc shim.Chaincode) {
+	if m.peer == nil {
+		m.peer = NewMockPeer()
+	}
+	m.peer.Register(name, cc)
+}
+
+// MockInvokeChaincode invokes a previously registered chaincode by name,
+// matching Fabric shimtest's inter-chaincode test ergonomics
+func (m *MockStub) MockInvokeChaincode(name string, args [][]byte) shim.Response {
+	if m.peer == nil {
+		return shim.Error(fmt.Sprintf("no chaincode registered as %s", name))
+	}
+	return m.peer.Invoke(name, args)
+}
+
+// --- shim.ChaincodeStubInterface ---
+
+func (m *MockStub) GetState(key string) ([]byte, error) {
+	return m.state[key], nil
+}
+
+func (m *MockStub) PutState(key string, value []byte) error {
+	if _, exists := m.state[key]; !exists {
+		m.insertKeyOrder(key)
+	}
+	m.state[key] = value
+	m.appendHistory(key, value, false)
+	return nil
+}
+
+func (m *MockStub) DelState(key string) error {
+	delete(m.state, key)
+	m.removeKeyOrder(key)
+	m.appendHistory(key, nil, true)
+	return nil
+}
+
+// appendHistory records a write or delete against key's version chain, so
+// GetHistoryForKey can replay it later
+func (m *MockStub) appendHistory(key string, value []byte, isDelete bool) {
+	m.history[key] = append(m.history[key], &shim.KeyModification{
+		TxID:      m.transactionID,
+		Timestamp: time.Now().Unix(),
+		Value:     value,
+		IsDelete:  isDelete,
+	})
+}
+
+// GetHistoryForKey returns key's recorded version chain, oldest first
+func (m *MockStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	return shim.NewMemoryHistoryIterator(m.history[key]), nil
+}
+
+func (m *MockStub) insertKeyOrder(key string) {
+	i := sort.SearchStrings(m.keyOrder, key)
+	m.keyOrder = append(m.keyOrder, "")
+	copy(m.keyOrder[i+1:], m.keyOrder[i:])
+	m.keyOrder[i] = key
+}
+
+func (m *MockStub) removeKeyOrder(key string) {
+	i := sort.SearchStrings(m.keyOrder, key)
+	if i < len(m.keyOrder) && m.keyOrder[i] == key {
+		m.keyOrder = append(m.keyOrder[:i], m.keyOrder[i+1:]...)
+	}
+}
+
+func (m *MockStub) rebuildKeyOrder() {
+	m.keyOrder = m.keyOrder[:0]
+	for k := range m.state {
+		m.insertKeyOrder(k)
+	}
+}
+
+func (m *MockStub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	var results []*shim.KeyValue
+	for _, key := range m.keyOrder {
+		if key >= startKey && (endKey == "" || key < endKey) {
+			results = append(results, &shim.KeyValue{Key: key, Value: m.state[key]})
+		}
+	}
+	return shim.NewMemoryIterator(results), nil
+}
+
+func (m *MockStub) GetStateByPartialCompositeKey(objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := shim.CreateCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*shim.KeyValue
+	for _, key := range m.keyOrder {
+		if strings.HasPrefix(key, prefix) {
+			results = append(results, &shim.KeyValue{Key: key, Value: m.state[key]})
+		}
+	}
+	return shim.NewMemoryIterator(results), nil
+}
+
+func (m *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *shim.QueryResponseMetadata, error) {
+	iter, err := m.GetStateByRange(startKey, endKey)
+	return paginate(iter, err, pageSize, bookmark)
+}
+
+func (m *MockStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *shim.QueryResponseMetadata, error) {
+	iter, err := m.GetStateByPartialCompositeKey(objectType, keys)
+	return paginate(iter, err, pageSize, bookmark)
+}
+
+// GetQueryResult supports a minimal flat equality selector, e.g.
+// `{"docType":"provenance","productID":"X"}`, matching every stored JSON
+// value whose top-level fields equal every key in the selector. This is
+// enough to exercise rich-query chaincode logic in unit tests without a
+// real document store.
+func (m *MockStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	var selector map[string]interface{}
+	if err := json.Unmarshal([]byte(query), &selector); err != nil {
+		return nil, fmt.Errorf("invalid query selector: %w", err)
+	}
+
+	var results []*shim.KeyValue
+	for _, key := range m.keyOrder {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(m.state[key], &doc); err != nil {
+			continue // not a JSON document, can't match a selector against it
+		}
+
+		if matchesSelector(doc, selector) {
+			results = append(results, &shim.KeyValue{Key: key, Value: m.state[key]})
+		}
+	}
+
+	return shim.NewMemoryIterator(results), nil
+}
+
+func (m *MockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *shim.QueryResponseMetadata, error) {
+	iter, err := m.GetQueryResult(query)
+	return paginate(iter, err, pageSize, bookmark)
+}
+
+func matchesSelector(doc, selector map[string]interface{}) bool {
+	for k, v := range selector {
+		docVal, ok := doc[k]
+		if !ok || fmt.Sprintf("%v", docVal) != fmt.Sprintf("%v", v) {
+			return false
+		}
+	}
+	return true
+}
+
+// paginate slices an already-materialized iterator's worth of results
+// into a single page; MockStub evaluates queries eagerly so pagination
+// here is a bookmark-driven offset.
+func paginate(iter shim.StateQueryIteratorInterface, err error, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *shim.QueryResponseMetadata, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+
+	offset := 0
+	if bookmark != "" {
+		fmt.Sscanf(bookmark, "%d", &offset)
+	}
+
+	var all []*shim.KeyValue
+	for iter.HasNext() {
+		kv, err := iter.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		all = append(all, kv)
+	}
+
+	end := len(all)
+	if pageSize > 0 && offset+int(pageSize) < end {
+		end = offset + int(pageSize)
+	}
+
+	var page []*shim.KeyValue
+	nextBookmark := ""
+	if offset < end {
+		page = all[offset:end]
+	}
+	if end < len(all) {
+		nextBookmark = fmt.Sprintf("%d", end)
+	}
+
+	return shim.NewMemoryIterator(page), &shim.QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(page)),
+		Bookmark:            nextBookmark,
+	}, nil
+}
+
+func (m *MockStub) GetTxID() string {
+	return m.transactionID
+}
+
+func (m *MockStub) GetChannelID() string {
+	return m.channelID
+}
+
+func (m *MockStub) GetCreator() ([]byte, error) {
+	return m.creator, nil
+}
+
+func (m *MockStub) GetTxTimestamp() (*time.Time, error) {
+	t := time.Unix(m.timestamp, 0)
+	return &t, nil
+}
+
+func (m *MockStub) SetEvent(name string, payload []byte) error {
+	m.events = append(m.events, recordedEvent{Name: name, Payload: payload})
+	return nil
+}
+
+func (m *MockStub) SubscribeEvents(filter shim.EventFilter) (shim.EventSubscription, error) {
+	return nil, fmt.Errorf("SubscribeEvents is not supported by MockStub")
+}
+
+func (m *MockStub) LogMessage(level shim.LogLevel, message string) error {
+	return nil
+}
+
+func (m *MockStub) GetFunctionAndParameters() (string, []string) {
+	strArgs := make([]string, len(m.args))
+	for i, a := range m.args {
+		strArgs[i] = string(a)
+	}
+	return m.function, strArgs
+}
+
+func (m *MockStub) GetStringArgs() []string {
+	_, args := m.GetFunctionAndParameters()
+	return args
+}
+
+func (m *MockStub) GetArgs() [][]byte {
+	return m.args
+}
+
+func (m *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) shim.Response {
+	return m.MockInvokeChaincode(chaincodeName, args)
+}
+
+func (m *MockStub) GetPrivateData(collection, key string) ([]byte, error) {
+	coll, ok := m.privateData[collection]
+	if !ok {
+		return nil, nil
+	}
+	return coll[key], nil
+}
+
+func (m *MockStub) PutPrivateData(collection, key string, value []byte) error {
+	if m.privateData[collection] == nil {
+		m.privateData[collection] = make(map[string][]byte)
+	}
+	m.privateData[collection][key] = value
+	return nil
+}
+
+func (m *MockStub) DelPrivateData(collection, key string) error {
+	if coll, ok := m.privateData[collection]; ok {
+		delete(coll, key)
+	}
+	return nil
+}
+
+func (m *MockStub) GetPrivateDataByRange(collection, startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	coll := m.privateData[collection]
+
+	var keys []string
+	for k := range coll {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var results []*shim.KeyValue
+	for _, key := range keys {
+		if key >= startKey && (endKey == "" || key < endKey) {
+			results = append(results, &shim.KeyValue{Key: key, Value: coll[key]})
+		}
+	}
+	return shim.NewMemoryIterator(results), nil
+}
+
+func (m *MockStub) GetPrivateDataHashByRange(collection, startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	coll := m.privateData[collection]
+
+	var keys []string
+	for k := range coll {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var results []*shim.KeyValue
+	for _, key := range keys {
+		if key >= startKey && (endKey == "" || key < endKey) {
+			hash := sha256.Sum256(coll[key])
+			results = append(results, &shim.KeyValue{Key: key, Value: hash[:]})
+		}
+	}
+	return shim.NewMemoryIterator(results), nil
+}
+
+func (m *MockStub) GetTransient() (map[string][]byte, error) {
+	return m.transient, nil
+}
@@ -0,0 +1,131 @@
+package shim
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+)
+
+// SignatureAlgorithm identifies a supported public-key signature scheme
+type SignatureAlgorithm string
+
+const (
+	// AlgorithmEd25519 verifies a raw Ed25519 signature
+	AlgorithmEd25519 SignatureAlgorithm = "Ed25519"
+	// AlgorithmECDSAP256 verifies an ASN.1 DER ECDSA signature over the
+	// NIST P-256 curve, against a SHA-256 digest of the message
+	AlgorithmECDSAP256 SignatureAlgorithm = "ECDSA-P256"
+)
+
+// VerifySignature reports whether signature is a valid signature over
+// message under the given algorithm, for a public key encoded as either a
+// PEM block or raw base64. Used to give chaincode functions proof of
+// possession of a registered key (e.g. gateway attestation) rather than
+// trusting a bare public key string.
+func VerifySignature(publicKeyEncoded string, algorithm SignatureAlgorithm, message, signature []byte) (bool, error) {
+	switch algorithm {
+	case AlgorithmEd25519:
+		pub, err := decodeEd25519PublicKey(publicKeyEncoded)
+		if err != nil {
+			return false, err
+		}
+		return ed25519.Verify(pub, message, signature), nil
+
+	case AlgorithmECDSAP256:
+		pub, err := decodeECDSAPublicKey(publicKeyEncoded)
+		if err != nil {
+			return false, err
+		}
+		digest := sha256.Sum256(message)
+		return ecdsa.VerifyASN1(pub, digest[:], signature), nil
+
+	default:
+		return false, fmt.Errorf("unsupported signature algorithm: %s", algorithm)
+	}
+}
+
+// GenerateNonce returns a random, hex-encoded nonce of size bytes, for use
+// as a single-use challenge in attestation protocols
+func GenerateNonce(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// DecodeSignature decodes a signature encoded as base64, falling back to
+// hex, which covers the two encodings clients commonly submit
+func DecodeSignature(encoded string) ([]byte, error) {
+	if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+		return decoded, nil
+	}
+	if decoded, err := hex.DecodeString(encoded); err == nil {
+		return decoded, nil
+	}
+	return nil, fmt.Errorf("signature is neither valid base64 nor hex")
+}
+
+func decodeEd25519PublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := decodeKeyBytes(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(raw); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Ed25519 public key: %w", err)
+		}
+		pub, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM key is not an Ed25519 public key")
+		}
+		return pub, nil
+	}
+
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key length: %d", len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func decodeECDSAPublicKey(encoded string) (*ecdsa.PublicKey, error) {
+	raw, err := decodeKeyBytes(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(raw); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ECDSA public key: %w", err)
+		}
+		pub, ok := parsed.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM key is not an ECDSA public key")
+		}
+		return pub, nil
+	}
+
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, raw)
+	if x == nil {
+		return nil, fmt.Errorf("invalid uncompressed ECDSA P-256 public key encoding")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeKeyBytes(encoded string) ([]byte, error) {
+	if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+		return decoded, nil
+	}
+	return []byte(encoded), nil
+}
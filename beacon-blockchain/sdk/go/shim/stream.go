@@ -0,0 +1,161 @@
+package shim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/beacon-blockchain/sdk-go/proto"
+)
+
+// peerStream multiplexes every shim<->peer operation over a single
+// bidirectional ChatWithPeer stream, keyed by a monotonically increasing
+// correlation ID. This lets a chaincode have many requests in flight at
+// once and lets the peer push asynchronous messages (READY, KEEPALIVE,
+// transaction lifecycle events) without opening a second channel.
+type peerStream struct {
+	stream pb.ChaincodeShim_ChatWithPeerClient
+
+	nextCorrelationID uint64
+
+	// sendMu serializes ps.stream.Send calls. grpc.ClientStream forbids
+	// concurrent SendMsg calls from multiple goroutines, and call() is
+	// invoked concurrently by design — it's what lets one chaincode have
+	// many requests in flight on this one multiplexed stream at once.
+	sendMu sync.Mutex
+
+	mu       sync.Mutex
+	pending  map[uint64]chan *pb.ChaincodeMessage
+	closed   bool
+	closeErr error
+}
+
+// newPeerStream opens the ChatWithPeer stream and starts the background
+// dispatch loop.
+func newPeerStream(ctx context.Context, client pb.ChaincodeShimClient) (*peerStream, error) {
+	stream, err := client.ChatWithPeer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open peer stream: %w", err)
+	}
+
+	ps := &peerStream{
+		stream:  stream,
+		pending: make(map[uint64]chan *pb.ChaincodeMessage),
+	}
+	go ps.recvLoop()
+
+	return ps, nil
+}
+
+// recvLoop ranges over stream.Recv() and routes each reply to the Go
+// channel registered for its correlation ID. Messages with no registered
+// waiter (e.g. unsolicited KEEPALIVE/READY pushes from the peer) are
+// dropped; callers that care about those use SubscribeEvents instead.
+func (ps *peerStream) recvLoop() {
+	for {
+		msg, err := ps.stream.Recv()
+		if err != nil {
+			ps.fail(err)
+			return
+		}
+
+		ps.mu.Lock()
+		ch, ok := ps.pending[msg.CorrelationId]
+		if ok {
+			delete(ps.pending, msg.CorrelationId)
+		}
+		ps.mu.Unlock()
+
+		if ok {
+			ch <- msg
+			close(ch)
+		}
+	}
+}
+
+// fail unblocks every in-flight call once the stream dies, e.g. on EOF
+// when the peer closes the connection.
+func (ps *peerStream) fail(err error) {
+	if err == io.EOF {
+		err = fmt.Errorf("peer closed the chaincode stream")
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.closed {
+		return
+	}
+	ps.closed = true
+	ps.closeErr = err
+
+	for id, ch := range ps.pending {
+		close(ch)
+		delete(ps.pending, id)
+	}
+}
+
+// call sends msgType/payload on the stream and blocks until the peer
+// replies with the matching correlation ID, the context is cancelled, or
+// the stream dies.
+func (ps *peerStream) call(ctx context.Context, msgType pb.ChaincodeMessage_Type, txID, channelID string, payload proto.Message) (*pb.ChaincodeMessage, error) {
+	body, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %v payload: %w", msgType, err)
+	}
+
+	correlationID := atomic.AddUint64(&ps.nextCorrelationID, 1)
+	replyCh := make(chan *pb.ChaincodeMessage, 1)
+
+	ps.mu.Lock()
+	if ps.closed {
+		err := ps.closeErr
+		ps.mu.Unlock()
+		return nil, fmt.Errorf("chaincode stream is closed: %w", err)
+	}
+	ps.pending[correlationID] = replyCh
+	ps.mu.Unlock()
+
+	req := &pb.ChaincodeMessage{
+		Type:          msgType,
+		CorrelationId: correlationID,
+		Payload:       body,
+		TxId:          txID,
+		ChannelId:     channelID,
+	}
+
+	ps.sendMu.Lock()
+	err = ps.stream.Send(req)
+	ps.sendMu.Unlock()
+	if err != nil {
+		ps.mu.Lock()
+		delete(ps.pending, correlationID)
+		ps.mu.Unlock()
+		return nil, fmt.Errorf("failed to send %v request: %w", msgType, err)
+	}
+
+	select {
+	case reply, ok := <-replyCh:
+		if !ok {
+			ps.mu.Lock()
+			err := ps.closeErr
+			ps.mu.Unlock()
+			return nil, fmt.Errorf("chaincode stream closed while waiting for reply: %w", err)
+		}
+		return reply, nil
+	case <-ctx.Done():
+		ps.mu.Lock()
+		delete(ps.pending, correlationID)
+		ps.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Close terminates the underlying stream.
+func (ps *peerStream) Close() error {
+	return ps.stream.CloseSend()
+}
@@ -0,0 +1,224 @@
+// Package table ports the Fabric "table" API on top of the shim's
+// composite-key state operations, so chaincode authors get a structured
+// record store instead of hand-rolling key encoding and row
+// serialization for every asset type.
+package table
+
+import (
+	"fmt"
+
+	"github.com/beacon-blockchain/sdk-go/shim"
+)
+
+// ColumnType enumerates the column kinds a Table supports
+type ColumnType int32
+
+const (
+	ColumnString ColumnType = iota
+	ColumnInt64
+	ColumnBytes
+	ColumnBool
+)
+
+// Column describes a single column in a Table
+type Column struct {
+	Name    string
+	Type    ColumnType
+	KeyPart bool // true if this column participates in the row's composite key
+}
+
+// Table is a descriptor for a structured record store backed by
+// composite-key state entries, one per row
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// Row is a single record: column name -> typed value
+type Row map[string]interface{}
+
+// NewTable declares a table descriptor and persists it so OpenTable can
+// load it by name; it fails if a table of that name already exists.
+// OpenTable returns the stored descriptor as is and does not check column
+// compatibility
+func NewTable(stub shim.ChaincodeStubInterface, name string, columns []Column) (*Table, error) {
+	t := &Table{Name: name, Columns: columns}
+
+	existing, err := stub.GetState(tableMetaKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing table %s: %w", name, err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("table already exists: %s", name)
+	}
+
+	if err := shim.PutStateAsJSON(stub, tableMetaKey(name), t); err != nil {
+		return nil, fmt.Errorf("failed to create table %s: %w", name, err)
+	}
+
+	return t, nil
+}
+
+// OpenTable loads a previously created table descriptor
+func OpenTable(stub shim.ChaincodeStubInterface, name string) (*Table, error) {
+	var t Table
+	if err := shim.GetStateAsJSON(stub, tableMetaKey(name), &t); err != nil {
+		return nil, fmt.Errorf("table not found: %s", name)
+	}
+	return &t, nil
+}
+
+// keyColumns returns the column names that make up a row's composite key,
+// in declaration order
+func (t *Table) keyColumns() []string {
+	var names []string
+	for _, col := range t.Columns {
+		if col.KeyPart {
+			names = append(names, col.Name)
+		}
+	}
+	return names
+}
+
+// rowKey builds the composite key for a row from its key-part columns
+func (t *Table) rowKey(row Row) (string, error) {
+	var attrs []string
+	for _, name := range t.keyColumns() {
+		v, ok := row[name]
+		if !ok {
+			return "", fmt.Errorf("row is missing key column %s", name)
+		}
+		attrs = append(attrs, fmt.Sprintf("%v", v))
+	}
+	return shim.CreateCompositeKey(t.Name, attrs)
+}
+
+// InsertRow validates and stores a new row, failing if one already exists
+// for the same key columns
+func (t *Table) InsertRow(stub shim.ChaincodeStubInterface, row Row) error {
+	if err := t.validateRow(row); err != nil {
+		return err
+	}
+
+	key, err := t.rowKey(row)
+	if err != nil {
+		return err
+	}
+
+	existing, err := stub.GetState(key)
+	if err != nil {
+		return fmt.Errorf("failed to check existing row: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("row already exists in table %s", t.Name)
+	}
+
+	return shim.PutStateAsJSON(stub, key, row)
+}
+
+// GetRow retrieves a single row by its key-part values, supplied in
+// declaration order
+func (t *Table) GetRow(stub shim.ChaincodeStubInterface, keyValues ...interface{}) (Row, error) {
+	attrs := make([]string, len(keyValues))
+	for i, v := range keyValues {
+		attrs[i] = fmt.Sprintf("%v", v)
+	}
+
+	key, err := shim.CreateCompositeKey(t.Name, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var row Row
+	if err := shim.GetStateAsJSON(stub, key, &row); err != nil {
+		return nil, fmt.Errorf("row not found in table %s", t.Name)
+	}
+
+	return row, nil
+}
+
+// GetRowsByPartialCompositeKey returns every row whose key columns match
+// the supplied prefix
+func (t *Table) GetRowsByPartialCompositeKey(stub shim.ChaincodeStubInterface, keyValues ...interface{}) ([]Row, error) {
+	attrs := make([]string, len(keyValues))
+	for i, v := range keyValues {
+		attrs[i] = fmt.Sprintf("%v", v)
+	}
+
+	iterator, err := stub.GetStateByPartialCompositeKey(t.Name, attrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table %s: %w", t.Name, err)
+	}
+	defer iterator.Close()
+
+	var rows []Row
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("error iterating table %s: %w", t.Name, err)
+		}
+
+		var row Row
+		if err := shim.Unmarshal(kv.Value, &row); err != nil {
+			return nil, fmt.Errorf("failed to decode row in table %s: %w", t.Name, err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// DeleteRow removes a row by its key-part values
+func (t *Table) DeleteRow(stub shim.ChaincodeStubInterface, keyValues ...interface{}) error {
+	attrs := make([]string, len(keyValues))
+	for i, v := range keyValues {
+		attrs[i] = fmt.Sprintf("%v", v)
+	}
+
+	key, err := shim.CreateCompositeKey(t.Name, attrs)
+	if err != nil {
+		return err
+	}
+
+	return stub.DelState(key)
+}
+
+// validateRow checks that every declared column, if present, holds a
+// value of the declared type
+func (t *Table) validateRow(row Row) error {
+	for _, col := range t.Columns {
+		v, ok := row[col.Name]
+		if !ok {
+			continue
+		}
+
+		switch col.Type {
+		case ColumnString:
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("column %s expects a string value", col.Name)
+			}
+		case ColumnInt64:
+			switch v.(type) {
+			case int, int32, int64, float64:
+			default:
+				return fmt.Errorf("column %s expects an integer value", col.Name)
+			}
+		case ColumnBytes:
+			switch v.(type) {
+			case []byte, string:
+			default:
+				return fmt.Errorf("column %s expects a bytes value", col.Name)
+			}
+		case ColumnBool:
+			if _, ok := v.(bool); !ok {
+				return fmt.Errorf("column %s expects a boolean value", col.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// tableMetaKey is the state key a table's descriptor is stored under
+func tableMetaKey(name string) string {
+	return "table_meta:" + name
+}
@@ -5,23 +5,36 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
-// CompositeKey creates a composite key from object type and attributes
+// CreateCompositeKey creates a composite key from object type and attributes.
+// objectType and every attribute are rejected if they contain the "\x00"
+// delimiter (which would desynchronize SplitCompositeKey) or are not valid
+// UTF-8.
 func CreateCompositeKey(objectType string, attributes []string) (string, error) {
 	if objectType == "" {
 		return "", fmt.Errorf("object type cannot be empty")
 	}
-	
+	if strings.Contains(objectType, "\x00") {
+		return "", fmt.Errorf("object type cannot contain null character")
+	}
+	if !utf8.ValidString(objectType) {
+		return "", fmt.Errorf("object type must be valid UTF-8")
+	}
+
 	// Join with a delimiter that's unlikely to appear in normal keys
 	key := objectType
 	for _, attr := range attributes {
 		if strings.Contains(attr, "\x00") {
 			return "", fmt.Errorf("attribute cannot contain null character")
 		}
+		if !utf8.ValidString(attr) {
+			return "", fmt.Errorf("attribute must be valid UTF-8")
+		}
 		key += "\x00" + attr
 	}
-	
+
 	return key, nil
 }
 
@@ -137,7 +150,31 @@ func GetStateAsJSON(stub ChaincodeStubInterface, key string, target interface{})
 	if value == nil {
 		return fmt.Errorf("key not found: %s", key)
 	}
-	
+
+	return json.Unmarshal(value, target)
+}
+
+// PutPrivateStateAsJSON saves a Go value as JSON into a private data
+// collection
+func PutPrivateStateAsJSON(stub ChaincodeStubInterface, collection, key string, value interface{}) error {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return stub.PutPrivateData(collection, key, jsonBytes)
+}
+
+// GetPrivateStateAsJSON retrieves a value from a private data collection
+// and unmarshals it from JSON
+func GetPrivateStateAsJSON(stub ChaincodeStubInterface, collection, key string, target interface{}) error {
+	value, err := stub.GetPrivateData(collection, key)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return fmt.Errorf("key not found in collection %s: %s", collection, key)
+	}
+
 	return json.Unmarshal(value, target)
 }
 